@@ -0,0 +1,180 @@
+// Package alerts provides a unified alert dispatcher, modeled on Lotus's
+// journal/alerting: named alert types raise and resolve with timestamps and
+// context, repeated firings are deduplicated within a cooldown window, and
+// each event fans out to every registered Sink.
+package alerts
+
+import (
+	"sync"
+	"time"
+)
+
+// Severity classifies how urgently an alert needs attention.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Event is one raise or resolve notification handed to every Sink.
+type Event struct {
+	AlertType string         `json:"alert_type"`
+	Severity  Severity       `json:"severity"`
+	Resolved  bool           `json:"resolved"`
+	Message   string         `json:"message"`
+	Context   map[string]any `json:"context,omitempty"`
+	Time      time.Time      `json:"time"`
+}
+
+// Sink delivers an Event to a destination (Telegram, Discord, desktop, etc).
+// Implementations should be non-blocking where possible; Alerter does not
+// serialize sink calls against each other.
+type Sink interface {
+	Name() string
+	Send(Event) error
+}
+
+// DefaultCooldown is how long an alert type+key must stay silent between
+// repeat firings once raised.
+const DefaultCooldown = 15 * time.Minute
+
+// activeAlert tracks the raise state for one (alertType, key) pair.
+type activeAlert struct {
+	lastRaised time.Time
+	raisedAt   time.Time
+}
+
+// Alerter registers named alert types, tracks their raise/resolve state, and
+// dispatches to all registered sinks while respecting a per-alert cooldown.
+type Alerter struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	sinks    []Sink
+	active   map[string]*activeAlert
+	history  []HistoryEntry
+}
+
+// HistoryEntry is an in-memory record of a raise/resolve transition, kept
+// alongside whatever durable storage (e.g. db.SaveAlertHistory) the caller
+// also wires up.
+type HistoryEntry struct {
+	Event    Event
+	SinkSent []string
+}
+
+// New creates an Alerter with the given cooldown (DefaultCooldown if <= 0)
+// and sinks.
+func New(cooldown time.Duration, sinks ...Sink) *Alerter {
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Alerter{
+		cooldown: cooldown,
+		sinks:    sinks,
+		active:   make(map[string]*activeAlert),
+	}
+}
+
+// AddSink registers an additional delivery backend.
+func (a *Alerter) AddSink(s Sink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sinks = append(a.sinks, s)
+}
+
+func alertKey(alertType, key string) string {
+	return alertType + "|" + key
+}
+
+// Raise fires an alert for (alertType, key) if it isn't already active and
+// within cooldown. Returns false if the raise was suppressed by cooldown.
+func (a *Alerter) Raise(alertType, key string, severity Severity, message string, context map[string]any) bool {
+	a.mu.Lock()
+	k := alertKey(alertType, key)
+	now := time.Now().UTC()
+
+	state, exists := a.active[k]
+	if exists && now.Sub(state.lastRaised) < a.cooldown {
+		a.mu.Unlock()
+		return false
+	}
+	if !exists {
+		state = &activeAlert{raisedAt: now}
+		a.active[k] = state
+	}
+	state.lastRaised = now
+	sinks := append([]Sink(nil), a.sinks...)
+	a.mu.Unlock()
+
+	event := Event{
+		AlertType: alertType,
+		Severity:  severity,
+		Resolved:  false,
+		Message:   message,
+		Context:   context,
+		Time:      now,
+	}
+	a.dispatch(event, sinks)
+	return true
+}
+
+// Resolve clears the active state for (alertType, key) and notifies sinks
+// that the condition cleared. No-op if the alert wasn't active.
+func (a *Alerter) Resolve(alertType, key, message string) {
+	a.mu.Lock()
+	k := alertKey(alertType, key)
+	if _, ok := a.active[k]; !ok {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.active, k)
+	sinks := append([]Sink(nil), a.sinks...)
+	a.mu.Unlock()
+
+	event := Event{
+		AlertType: alertType,
+		Resolved:  true,
+		Message:   message,
+		Time:      time.Now().UTC(),
+	}
+	a.dispatch(event, sinks)
+}
+
+func (a *Alerter) dispatch(event Event, sinks []Sink) {
+	sent := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		if err := s.Send(event); err == nil {
+			sent = append(sent, s.Name())
+		}
+	}
+	a.mu.Lock()
+	a.history = append(a.history, HistoryEntry{Event: event, SinkSent: sent})
+	if len(a.history) > 1000 {
+		a.history = a.history[len(a.history)-1000:]
+	}
+	a.mu.Unlock()
+}
+
+// ActiveAlerts returns the alertType+key pairs currently raised, for
+// rendering a UI status badge.
+func (a *Alerter) ActiveAlerts() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]string, 0, len(a.active))
+	for k := range a.active {
+		out = append(out, k)
+	}
+	return out
+}
+
+// RecentHistory returns the last n dispatched events (0 = all buffered).
+func (a *Alerter) RecentHistory(n int) []HistoryEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n <= 0 || n >= len(a.history) {
+		return append([]HistoryEntry(nil), a.history...)
+	}
+	return append([]HistoryEntry(nil), a.history[len(a.history)-n:]...)
+}
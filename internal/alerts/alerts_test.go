@@ -0,0 +1,51 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	events []Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+func (s *recordingSink) Send(e Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestAlerter_RaiseDedupesWithinCooldown(t *testing.T) {
+	sink := &recordingSink{}
+	a := New(time.Hour, sink)
+
+	if !a.Raise("margin_breach", "34", SeverityWarning, "first", nil) {
+		t.Fatal("first raise should fire")
+	}
+	if a.Raise("margin_breach", "34", SeverityWarning, "second", nil) {
+		t.Fatal("second raise within cooldown should be suppressed")
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("sink received %d events, want 1", len(sink.events))
+	}
+}
+
+func TestAlerter_ResolveClearsActiveState(t *testing.T) {
+	sink := &recordingSink{}
+	a := New(time.Hour, sink)
+
+	a.Raise("margin_breach", "34", SeverityWarning, "firing", nil)
+	if len(a.ActiveAlerts()) != 1 {
+		t.Fatalf("ActiveAlerts() len = %d, want 1", len(a.ActiveAlerts()))
+	}
+
+	a.Resolve("margin_breach", "34", "cleared")
+	if len(a.ActiveAlerts()) != 0 {
+		t.Fatalf("ActiveAlerts() after resolve len = %d, want 0", len(a.ActiveAlerts()))
+	}
+
+	// After resolve, a new raise should fire immediately regardless of cooldown.
+	if !a.Raise("margin_breach", "34", SeverityWarning, "re-firing", nil) {
+		t.Fatal("raise after resolve should fire")
+	}
+}
@@ -0,0 +1,28 @@
+package alerts
+
+import (
+	"eve-flipper/internal/logger"
+)
+
+// logForwardSink implements logger.Sink, forwarding Warn/Error records into
+// the Alerter so critical scan failures reach Telegram/Discord/desktop the
+// same way a watchlist threshold breach would.
+type logForwardSink struct {
+	alerter *Alerter
+}
+
+// AsLogSink adapts the Alerter into a logger.Sink. Attach it with
+// logger.Default.AddSink(alerter.AsLogSink()) to forward Warn/Error log
+// records as alerts, deduplicated per tag via the normal cooldown.
+func (a *Alerter) AsLogSink() logger.Sink {
+	return logForwardSink{alerter: a}
+}
+
+func (s logForwardSink) Write(r logger.Record) {
+	switch r.Level {
+	case logger.LevelError.String():
+		s.alerter.Raise("log_error", r.Tag, SeverityCritical, r.Msg, r.Fields)
+	case logger.LevelWarn.String():
+		s.alerter.Raise("log_warn", r.Tag, SeverityWarning, r.Msg, r.Fields)
+	}
+}
@@ -0,0 +1,90 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// TelegramSink posts alert messages to a Telegram chat via the Bot API.
+type TelegramSink struct {
+	BotToken string
+	ChatID   string
+}
+
+func (s *TelegramSink) Name() string { return "telegram" }
+
+func (s *TelegramSink) Send(event Event) error {
+	if s.BotToken == "" || s.ChatID == "" {
+		return fmt.Errorf("telegram sink not configured")
+	}
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.BotToken)
+	payload, _ := json.Marshal(map[string]string{
+		"chat_id": s.ChatID,
+		"text":    formatEventText(event),
+	})
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram sendMessage: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DiscordSink posts alert messages to a Discord webhook.
+type DiscordSink struct {
+	WebhookURL string
+}
+
+func (s *DiscordSink) Name() string { return "discord" }
+
+func (s *DiscordSink) Send(event Event) error {
+	if s.WebhookURL == "" {
+		return fmt.Errorf("discord sink not configured")
+	}
+	payload, _ := json.Marshal(map[string]string{"content": formatEventText(event)})
+	resp, err := httpClient.Post(s.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DesktopSink hands events to a callback that triggers an OS-level
+// notification (or a toast in the frontend, as the existing AlertDesktop
+// config flag does today).
+type DesktopSink struct {
+	Notify func(title, body string)
+}
+
+func (s *DesktopSink) Name() string { return "desktop" }
+
+func (s *DesktopSink) Send(event Event) error {
+	if s.Notify == nil {
+		return fmt.Errorf("desktop sink not configured")
+	}
+	title := event.AlertType
+	if event.Resolved {
+		title = "Resolved: " + title
+	}
+	s.Notify(title, event.Message)
+	return nil
+}
+
+func formatEventText(event Event) string {
+	if event.Resolved {
+		return fmt.Sprintf("[RESOLVED] %s: %s", event.AlertType, event.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", event.Severity, event.AlertType, event.Message)
+}
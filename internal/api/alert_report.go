@@ -0,0 +1,28 @@
+package api
+
+import (
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/esi"
+)
+
+// DefaultAlertReportLookaheadHours is how far past each alert's sent_at
+// AlertPerformanceReport looks for a later price when lookaheadHours <= 0.
+const DefaultAlertReportLookaheadHours = 24
+
+// AlertPerformanceReport evaluates how watchlist alerts for typeID (or every
+// watched item, if typeID is 0) actually performed after they fired, by
+// joining alert_history against history — the caller-preloaded market
+// history per type (mirroring backtest.HistoryByType, since neither db nor
+// api make live ESI calls of their own). Wired up behind GET
+// /api/alerts/report so users can tune AlertThreshold/AlertMetric from
+// evidence instead of guesswork.
+func (s *Server) AlertPerformanceReport(typeID int32, lookaheadHours int, history map[int32][]esi.HistoryEntry) (db.AlertStats, error) {
+	if lookaheadHours <= 0 {
+		lookaheadHours = DefaultAlertReportLookaheadHours
+	}
+	outcomes, err := s.db.GetAlertHistoryWithOutcomes(typeID, lookaheadHours, history)
+	if err != nil {
+		return db.AlertStats{}, err
+	}
+	return db.ComputeAlertStats(outcomes), nil
+}
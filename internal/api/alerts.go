@@ -11,6 +11,13 @@ import (
 const (
 	// DefaultAlertCooldownSeconds is the minimum time between repeat alerts for the same item/metric/threshold.
 	DefaultAlertCooldownSeconds = 3600 // 1 hour
+
+	// DefaultAlertResolveHysteresis keeps a resolved incident from
+	// immediately re-opening on the next scan if the metric is hovering
+	// right at threshold: it must drop at least this fraction below
+	// threshold, not just below it, before CheckWatchlistAlerts emits a
+	// Resolved event and clears the alert_active row.
+	DefaultAlertResolveHysteresis = 0.05 // 5%
 )
 
 // AlertCheckResult describes whether an alert should be sent and contains necessary metadata.
@@ -24,13 +31,46 @@ type AlertCheckResult struct {
 	Message        string
 	CooldownActive bool
 	LastAlertAt    time.Time
+
+	// Trailing is true when this alert fired from a pullback off a peak
+	// (see evaluateTrailingAlert) rather than a flat threshold breach.
+	Trailing  bool
+	PeakValue float64
+	ArmedTier int
+
+	// DedupKey is what actually gets persisted to alert_history and re-queried
+	// for cooldown purposes. It equals Threshold for ordinary metrics, but for
+	// the pivot-breakout metrics (break_prev_high/break_prev_low) it's the
+	// underlying pivot price instead: a new pivot almost always carries a new
+	// price, so keying dedup on it naturally resets the cooldown when a new
+	// pivot forms, without needing a separate pivot-timestamp column.
+	DedupKey float64
+
+	// Fingerprint stably identifies this (item, metric, threshold) incident
+	// across scans (see db.ComputeAlertFingerprint), so SendAlert can upsert
+	// alert_active and outbound channels can edit the prior message instead
+	// of posting a new one every time the same condition re-fires.
+	Fingerprint string
+}
+
+// AlertResolution is emitted by CheckWatchlistAlerts when a previously-open
+// incident's metric falls back under threshold by at least
+// DefaultAlertResolveHysteresis, closing out the incident in alert_active.
+type AlertResolution struct {
+	TypeID      int32
+	TypeName    string
+	Metric      string
+	Fingerprint string
+	Message     string
 }
 
 // CheckWatchlistAlerts evaluates watchlist items against scan results and determines which alerts to fire.
-// Returns list of alerts that should be sent (respecting cooldown and deduplication).
-func (s *Server) CheckWatchlistAlerts(results interface{}) []AlertCheckResult {
+// Returns the alerts that should be sent (respecting cooldown and deduplication), plus any Resolved
+// events for incidents whose metric has fallen back under threshold by DefaultAlertResolveHysteresis.
+func (s *Server) CheckWatchlistAlerts(results interface{}) ([]AlertCheckResult, []AlertResolution) {
 	watchlist := s.db.GetWatchlist()
 	var alerts []AlertCheckResult
+	var resolutions []AlertResolution
 
 	for _, item := range watchlist {
 		if !item.AlertEnabled {
@@ -55,13 +95,70 @@ func (s *Server) CheckWatchlistAlerts(results interface{}) []AlertCheckResult {
 			continue // item not found in results
 		}
 
+		fingerprint := db.ComputeAlertFingerprint(item.TypeID, metric, threshold)
+
+		// TrailingActivationRatios/TrailingCallbackRates are per-item config
+		// (internal/config.WatchlistItem), persisted alongside the other
+		// alert_* watchlist columns; trailing only activates once both are
+		// configured and line up 1:1 (one callback rate per activation tier).
+		trailingConfigured := len(item.TrailingActivationRatios) > 0 &&
+			len(item.TrailingActivationRatios) == len(item.TrailingCallbackRates)
+
 		// Check if threshold is met
 		if currentValue < threshold {
+			if trailingConfigured {
+				// The metric fell back below the base threshold: drop any armed
+				// trailing state so the next breach re-arms from tier -1 instead
+				// of resuming a stale peak.
+				if err := s.db.ResetAlertTrailState(item.TypeID, metric); err != nil {
+					log.Printf("[ALERT] Error resetting trail state for type %d: %v", item.TypeID, err)
+				}
+			}
+			if currentValue < threshold*(1-DefaultAlertResolveHysteresis) {
+				resolved, err := s.db.ResolveAlert(fingerprint)
+				if err != nil {
+					log.Printf("[ALERT] Error resolving alert for type %d: %v", item.TypeID, err)
+				} else if resolved {
+					resolutions = append(resolutions, AlertResolution{
+						TypeID:      item.TypeID,
+						TypeName:    typeName,
+						Metric:      metric,
+						Fingerprint: fingerprint,
+						Message:     fmt.Sprintf("%s: %s back to %.2f, below %.2f — resolved", typeName, metric, currentValue, threshold),
+					})
+				}
+			}
 			continue
 		}
 
+		var message string
+		var trailing bool
+		var peakValue float64
+		var armedTier int
+
+		if trailingConfigured {
+			fired, peak, tier := s.evaluateTrailingAlert(item.TypeID, metric, threshold, currentValue,
+				item.TrailingActivationRatios, item.TrailingCallbackRates)
+			if !fired {
+				continue // armed but hasn't pulled back far enough yet
+			}
+			trailing = true
+			peakValue = peak
+			armedTier = tier
+			message = s.formatTrailingAlertMessage(typeName, metric, peak, currentValue, tier)
+		} else {
+			message = s.formatAlertMessage(typeName, metric, threshold, currentValue)
+		}
+
+		dedupKey := threshold
+		if metric == "break_prev_high" || metric == "break_prev_low" {
+			if pivotPrice, ok := extractPivotPrice(item.TypeID, metric, results); ok {
+				dedupKey = pivotPrice
+			}
+		}
+
 		// Check cooldown (deduplication)
-		lastAlertTime, err := s.db.GetLastAlertTime(item.TypeID, metric, threshold)
+		lastAlertTime, err := s.db.GetLastAlertTime(item.TypeID, metric, dedupKey)
 		if err != nil {
 			log.Printf("[ALERT] Error checking last alert time for type %d: %v", item.TypeID, err)
 			continue
@@ -77,9 +174,6 @@ func (s *Server) CheckWatchlistAlerts(results interface{}) []AlertCheckResult {
 			}
 		}
 
-		// Generate alert message
-		message := s.formatAlertMessage(typeName, metric, threshold, currentValue)
-
 		alerts = append(alerts, AlertCheckResult{
 			ShouldAlert:    true,
 			TypeID:         item.TypeID,
@@ -90,16 +184,23 @@ func (s *Server) CheckWatchlistAlerts(results interface{}) []AlertCheckResult {
 			Message:        message,
 			CooldownActive: cooldownActive,
 			LastAlertAt:    lastAlertTime,
+			Trailing:       trailing,
+			PeakValue:      peakValue,
+			ArmedTier:      armedTier,
+			DedupKey:       dedupKey,
+			Fingerprint:    fingerprint,
 		})
 	}
 
-	return alerts
+	return alerts, resolutions
 }
 
 // SendAlert sends an alert via configured channels and records it in history.
+// The fingerprint is threaded through to the channel senders so Telegram/Discord
+// can edit the prior message for this incident instead of posting a new one.
 func (s *Server) SendAlert(alert AlertCheckResult, scanID *int64) error {
 	// Send via configured channels
-	result := s.sendConfiguredExternalAlerts(alert.Message)
+	result := s.sendConfiguredExternalAlerts(alert.Message, alert.Fingerprint)
 
 	// Record in history
 	channelsSent := result.Sent
@@ -114,13 +215,14 @@ func (s *Server) SendAlert(alert AlertCheckResult, scanID *int64) error {
 		WatchlistTypeID: alert.TypeID,
 		TypeName:        alert.TypeName,
 		AlertMetric:     alert.Metric,
-		AlertThreshold:  alert.Threshold,
+		AlertThreshold:  alert.DedupKey,
 		CurrentValue:    alert.CurrentValue,
 		Message:         alert.Message,
 		ChannelsSent:    channelsSent,
 		ChannelsFailed:  channelsFailed,
 		SentAt:          time.Now().UTC().Format(time.RFC3339),
 		ScanID:          scanID,
+		Fingerprint:     alert.Fingerprint,
 	}
 
 	if err := s.db.SaveAlertHistory(entry); err != nil {
@@ -128,10 +230,62 @@ func (s *Server) SendAlert(alert AlertCheckResult, scanID *int64) error {
 		// Don't fail the alert send if history save fails
 	}
 
-	log.Printf("[ALERT] Sent alert for %s: %s (channels: %v)", alert.TypeName, alert.Message, channelsSent)
+	if err := s.db.UpsertActiveAlert(db.ActiveWatchlistAlert{
+		Fingerprint:     alert.Fingerprint,
+		WatchlistTypeID: alert.TypeID,
+		Metric:          alert.Metric,
+		Threshold:       alert.DedupKey,
+		Message:         alert.Message,
+	}); err != nil {
+		log.Printf("[ALERT] Failed to upsert active alert incident for %s: %v", alert.TypeName, err)
+	}
+
+	log.Printf("[ALERT] Sent alert for %s: %s (channels: %v, fingerprint: %s)", alert.TypeName, alert.Message, channelsSent, alert.Fingerprint)
 	return nil
 }
 
+// ResolveWatchlistAlerts clears the active-alert state for any watchlist item
+// that no longer breaches its threshold in the latest scan results, so the
+// UI status badge drops it without waiting for the cooldown to expire. This
+// resolves through the same fingerprint-keyed alert_active incident CheckWatchlistAlerts
+// raises (db.ResolveAlert), rather than a separate tracker, so SendAlert/
+// ResolveWatchlistAlerts agree on a single active-incident path.
+func (s *Server) ResolveWatchlistAlerts(results interface{}) {
+	watchlist := s.db.GetWatchlist()
+	for _, item := range watchlist {
+		if !item.AlertEnabled {
+			continue
+		}
+		metric := item.AlertMetric
+		if metric == "" {
+			metric = "margin_percent"
+		}
+		threshold := item.AlertThreshold
+		if threshold <= 0 {
+			threshold = item.AlertMinMargin
+		}
+		if threshold <= 0 {
+			continue
+		}
+
+		currentValue, _, ok := s.extractMetricValue(item.TypeID, metric, results)
+		if ok && currentValue >= threshold {
+			continue // still breaching, leave the alert active
+		}
+
+		fingerprint := db.ComputeAlertFingerprint(item.TypeID, metric, threshold)
+		if _, err := s.db.ResolveAlert(fingerprint); err != nil {
+			log.Printf("[ALERT] Failed to resolve active alert for type %d: %v", item.TypeID, err)
+		}
+	}
+}
+
+// ActiveAlerts returns the currently open watchlist alerts for rendering a
+// status badge in the UI.
+func (s *Server) ActiveAlerts() ([]db.ActiveWatchlistAlert, error) {
+	return s.db.GetActiveAlerts()
+}
+
 // extractMetricValue extracts the current value for a given metric from scan results.
 // Supports FlipResult, StationTrade, ContractResult, etc.
 func (s *Server) extractMetricValue(typeID int32, metric string, results interface{}) (float64, string, bool) {
@@ -171,6 +325,20 @@ func extractFlipMetric(item FlipResult, metric string) float64 {
 		return item.ProfitPerUnit
 	case "daily_volume":
 		return float64(item.DailyVolume)
+	case "atr_percent":
+		return item.ATRPercent
+	case "margin_over_atr":
+		return safeDivAlerts(item.MarginPercent, item.ATRPercent)
+	case "break_prev_high":
+		if item.PivotHigh <= 0 {
+			return 0
+		}
+		return (item.SellPrice - item.PivotHigh) / item.PivotHigh * 100
+	case "break_prev_low":
+		if item.PivotLow <= 0 {
+			return 0
+		}
+		return (item.PivotLow - item.BuyPrice) / item.PivotLow * 100
 	default:
 		return 0
 	}
@@ -186,9 +354,65 @@ func extractStationMetric(item StationTrade, metric string) float64 {
 		return item.Margin
 	case "daily_volume":
 		return item.BuyVolume + item.SellVolume // approximation
+	case "atr_percent":
+		return item.ATRPercent
+	case "margin_over_atr":
+		return safeDivAlerts(item.MarginPct, item.ATRPercent)
+	case "break_prev_high":
+		if item.PivotHigh <= 0 {
+			return 0
+		}
+		return (item.SellPrice - item.PivotHigh) / item.PivotHigh * 100
+	case "break_prev_low":
+		if item.PivotLow <= 0 {
+			return 0
+		}
+		return (item.PivotLow - item.BuyPrice) / item.PivotLow * 100
+	default:
+		return 0
+	}
+}
+
+// extractPivotPrice returns the underlying pivot price backing a
+// break_prev_high/break_prev_low metric, for use as a cooldown dedup key
+// (see AlertCheckResult.DedupKey) rather than as the alerted value itself.
+func extractPivotPrice(typeID int32, metric string, results interface{}) (float64, bool) {
+	switch r := results.(type) {
+	case []FlipResult:
+		for _, item := range r {
+			if item.TypeID == typeID {
+				return pivotPriceForMetric(item.PivotHigh, item.PivotLow, metric)
+			}
+		}
+	case []StationTrade:
+		for _, item := range r {
+			if item.TypeID == typeID {
+				return pivotPriceForMetric(item.PivotHigh, item.PivotLow, metric)
+			}
+		}
+	}
+	return 0, false
+}
+
+func pivotPriceForMetric(pivotHigh, pivotLow float64, metric string) (float64, bool) {
+	switch metric {
+	case "break_prev_high":
+		return pivotHigh, pivotHigh > 0
+	case "break_prev_low":
+		return pivotLow, pivotLow > 0
 	default:
+		return 0, false
+	}
+}
+
+// safeDivAlerts divides margin by ATRPercent for the margin_over_atr metric,
+// returning 0 when ATRPercent is unusable (no history, or the item is
+// perfectly flat) rather than dividing by zero.
+func safeDivAlerts(numerator, denominator float64) float64 {
+	if denominator <= 0 {
 		return 0
 	}
+	return numerator / denominator
 }
 
 func (s *Server) formatAlertMessage(typeName, metric string, threshold, current float64) string {
@@ -206,11 +430,96 @@ func (s *Server) formatAlertMessage(typeName, metric string, threshold, current
 	case "daily_volume":
 		metricLabel = "Daily Volume"
 		return fmt.Sprintf("%s: %s %.0f >= %.0f", typeName, metricLabel, current, threshold)
+	case "atr_percent":
+		metricLabel = "Volatility (ATR%)"
+		return fmt.Sprintf("%s: %s %.2f%% >= %.2f%%", typeName, metricLabel, current, threshold)
+	case "margin_over_atr":
+		metricLabel = "Margin/ATR"
+		return fmt.Sprintf("%s: %s %.2f >= %.2f", typeName, metricLabel, current, threshold)
+	case "break_prev_high":
+		metricLabel = "Break Above Pivot High"
+		return fmt.Sprintf("%s: %s %.2f%% >= %.2f%%", typeName, metricLabel, current, threshold)
+	case "break_prev_low":
+		metricLabel = "Break Below Pivot Low"
+		return fmt.Sprintf("%s: %s %.2f%% >= %.2f%%", typeName, metricLabel, current, threshold)
 	default:
 		return fmt.Sprintf("%s: %s %.2f >= %.2f", typeName, metric, current, threshold)
 	}
 }
 
+// evaluateTrailingAlert updates the persisted peak/armed-tier state for
+// (typeID, metric) and reports whether the trailing pullback should fire.
+// Mirrors engine.TrackedPosition.OnPriceUpdate's tier-walk, but measured as
+// a ratio above threshold instead of a ratio above entry price: tier i arms
+// once (peak-threshold)/threshold crosses activationRatios[i], and once
+// armed, fires when the pullback from peak reaches callbackRates[tier].
+func (s *Server) evaluateTrailingAlert(typeID int32, metric string, threshold, current float64, activationRatios, callbackRates []float64) (fired bool, peak float64, tier int) {
+	state, ok := s.db.GetAlertTrailState(typeID, metric)
+	tier = -1
+	peak = current
+	if ok {
+		peak = state.Peak
+		tier = state.ActiveTier
+		if current > peak {
+			peak = current
+		}
+	}
+
+	// Promote to the furthest tier whose activation ratio has been reached.
+	for i := len(activationRatios) - 1; i > tier; i-- {
+		armThreshold := threshold * (1 + activationRatios[i])
+		if peak >= armThreshold {
+			tier = i
+			break
+		}
+	}
+
+	if tier < 0 {
+		if err := s.db.SaveAlertTrailState(db.AlertTrailState{WatchlistTypeID: typeID, Metric: metric, Peak: peak, ActiveTier: tier}); err != nil {
+			log.Printf("[ALERT] Error saving trail state for type %d: %v", typeID, err)
+		}
+		return false, peak, tier
+	}
+
+	retracement := (peak - current) / peak
+	fired = retracement >= callbackRates[tier]
+
+	if fired {
+		// Reset so the next breach re-arms from tier -1 rather than firing
+		// again on every subsequent scan while price idles below the peak.
+		if err := s.db.ResetAlertTrailState(typeID, metric); err != nil {
+			log.Printf("[ALERT] Error resetting trail state for type %d: %v", typeID, err)
+		}
+	} else if err := s.db.SaveAlertTrailState(db.AlertTrailState{WatchlistTypeID: typeID, Metric: metric, Peak: peak, ActiveTier: tier}); err != nil {
+		log.Printf("[ALERT] Error saving trail state for type %d: %v", typeID, err)
+	}
+	return fired, peak, tier
+}
+
+func (s *Server) formatTrailingAlertMessage(typeName, metric string, peak, current float64, tier int) string {
+	metricLabel := metric
+	switch metric {
+	case "margin_percent":
+		metricLabel = "Margin"
+	case "total_profit":
+		metricLabel = "Total Profit"
+	case "profit_per_unit":
+		metricLabel = "Profit/Unit"
+	case "daily_volume":
+		metricLabel = "Daily Volume"
+	case "atr_percent":
+		metricLabel = "Volatility (ATR%)"
+	case "margin_over_atr":
+		metricLabel = "Margin/ATR"
+	case "break_prev_high":
+		metricLabel = "Break Above Pivot High"
+	case "break_prev_low":
+		metricLabel = "Break Below Pivot Low"
+	}
+	return fmt.Sprintf("%s: %s pulled back to %.2f from peak %.2f (tier %d) — consider selling now",
+		typeName, metricLabel, current, peak, tier+1)
+}
+
 // FlipResult is duplicated here to avoid import cycle (should be in engine package).
 // This is a temporary workaround.
 type FlipResult struct {
@@ -227,6 +536,10 @@ type FlipResult struct {
 	SellStation    string
 	BuySystemName  string
 	SellSystemName string
+	ATR            float64 // Wilder-smoothed Average True Range, from esi.MarketStats
+	ATRPercent     float64 // ATR normalized by mid-price, as a percent
+	PivotHigh      float64 // most recent confirmed pivot high price, from esi.MarketStats
+	PivotLow       float64 // most recent confirmed pivot low price, from esi.MarketStats
 }
 
 // StationTrade is duplicated here to avoid import cycle.
@@ -240,6 +553,10 @@ type StationTrade struct {
 	Volume     float64
 	BuyVolume  float64
 	SellVolume float64
+	ATR        float64 // Wilder-smoothed Average True Range, from esi.MarketStats
+	ATRPercent float64 // ATR normalized by mid-price, as a percent
+	PivotHigh  float64 // most recent confirmed pivot high price, from esi.MarketStats
+	PivotLow   float64 // most recent confirmed pivot low price, from esi.MarketStats
 }
 
 // ContractResult is duplicated here to avoid import cycle.
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"log"
+
+	"eve-flipper/internal/engine"
+)
+
+// AddPositionRequest is the payload for entering a flip as a tracked
+// position for trailing take-profit exits.
+type AddPositionRequest struct {
+	TypeID     int32                 `json:"type_id"`
+	TypeName   string                `json:"type_name"`
+	EntryPrice float64               `json:"entry_price"`
+	Quantity   int64                 `json:"quantity"`
+	Tiers      []engine.TrailingTier `json:"tiers,omitempty"`
+}
+
+// AddPosition enters a new tracked position, persists it, and starts
+// trailing it for live price updates.
+func (s *Server) AddPosition(req AddPositionRequest) (*engine.TrackedPosition, error) {
+	pos := engine.NewTrackedPosition(0, req.TypeID, req.TypeName, req.EntryPrice, req.Quantity, req.Tiers)
+
+	id, err := s.db.AddPosition(pos)
+	if err != nil {
+		return nil, fmt.Errorf("save position: %w", err)
+	}
+	pos.ID = id
+
+	s.trailing.Track(pos)
+	return pos, nil
+}
+
+// ClosePosition stops trailing a position and marks it closed.
+func (s *Server) ClosePosition(id int64) error {
+	s.trailing.Untrack(id)
+	return s.db.ClosePosition(id)
+}
+
+// ListPositions returns the currently tracked open positions.
+func (s *Server) ListPositions() []*engine.TrackedPosition {
+	return s.trailing.List()
+}
+
+// OnPriceUpdate feeds a live best-bid price for typeID to every position
+// tracking it, firing a "sell now" alert through the alerts subsystem when
+// a position's trailing tier retraces past its callback rate.
+func (s *Server) OnPriceUpdate(typeID int32, price float64) {
+	for _, pos := range s.trailing.List() {
+		if pos.TypeID != typeID {
+			continue
+		}
+		if sellNow := pos.OnPriceUpdate(price); sellNow {
+			key := fmt.Sprintf("%d", pos.ID)
+			msg := fmt.Sprintf("%s: sell now, retraced from high %.2f to %.2f", pos.TypeName, pos.HighWaterMark, price)
+			s.alerter.Raise("trailing_take_profit", key, "warning", msg, nil)
+		}
+		if err := s.db.UpdatePositionState(pos.ID, pos.ActiveTier, pos.HighWaterMark); err != nil {
+			log.Printf("[POSITIONS] Failed to persist tier state for position %d: %v", pos.ID, err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Package backtest replays stored market history through the scan engine
+// day-by-day so strategy parameters can be tuned against real history before
+// being deployed live.
+package backtest
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+// Config describes one backtest run.
+type Config struct {
+	StartDate        string // YYYY-MM-DD, inclusive
+	EndDate          string // YYYY-MM-DD, inclusive
+	StartingBalance  float64
+	ParticipationCap float64 // max fraction of a day's volume a simulated fill may consume
+}
+
+// defaultHoldDays is how long a simulated position is held before being
+// unwound, standing in for whatever exit timing the live strategy would
+// have used.
+const defaultHoldDays = 3
+
+// SimulatedTrade is one round-trip (buy then sell) recorded during replay.
+type SimulatedTrade struct {
+	Date       string  `json:"date"`
+	ExitDate   string  `json:"exit_date"`
+	TypeID     int32   `json:"type_id"`
+	Quantity   float64 `json:"quantity"`
+	FillPrice  float64 `json:"fill_price"`
+	ExitPrice  float64 `json:"exit_price"`
+	ImpactPct  float64 `json:"impact_pct"`
+	GrossValue float64 `json:"gross_value"`
+	Profit     float64 `json:"profit"`
+	Rejected   bool    `json:"rejected"`
+}
+
+// Report is the outcome of a backtest run, mirroring the shape of the
+// engine's strategy stats so the same charting code can render either.
+type Report struct {
+	StartingBalance float64          `json:"starting_balance"`
+	FinalBalance    float64          `json:"final_balance"`
+	DailyPnL        []float64        `json:"daily_pnl"`
+	Dates           []string         `json:"dates"`
+	SharpeRatio     float64          `json:"sharpe_ratio"`
+	SortinoRatio    float64          `json:"sortino_ratio"`
+	ProfitFactor    float64          `json:"profit_factor"`
+	WinningRatio    float64          `json:"winning_ratio"`
+	MaxDrawdown     float64          `json:"max_drawdown"`
+	AvgHoldDays     float64          `json:"avg_hold_days"`
+	Trades          []SimulatedTrade `json:"trades"`
+}
+
+// HistoryByType maps type IDs to their chronologically-sorted market history
+// for the backtest's target region(s). Callers are expected to preload this
+// from the esi.HistoryCache (or a snapshotted order-book store) before
+// calling Run, since the backtest itself makes no live ESI calls.
+type HistoryByType map[int32][]esi.HistoryEntry
+
+// Run replays history day-by-day, calibrating the impact model from the
+// trailing window available at each simulated day, and applying it to fill
+// simulated orders sized by simulatedOrderSize. Fills that would exceed
+// ParticipationCap of that day's volume are rejected rather than filled.
+func Run(cfg Config, history HistoryByType) *Report {
+	report := &Report{StartingBalance: cfg.StartingBalance, FinalBalance: cfg.StartingBalance}
+	if cfg.ParticipationCap <= 0 {
+		cfg.ParticipationCap = 0.35
+	}
+
+	dates := simulationDates(cfg.StartDate, cfg.EndDate)
+	if len(dates) == 0 {
+		return report
+	}
+
+	balance := cfg.StartingBalance
+	var dailyPnL []float64
+	var holdDaysSum float64
+	var holdCount int
+
+	for _, date := range dates {
+		dayPnL := 0.0
+
+		for typeID, entries := range history {
+			window := entriesUpTo(entries, date, engine.DefaultImpactDays)
+			if len(window) < 5 {
+				continue
+			}
+			today := entryOn(entries, date)
+			if today == nil || today.Average <= 0 {
+				continue
+			}
+
+			params := engine.CalibrateImpact(window, engine.DefaultImpactDays)
+			if !params.Valid {
+				continue
+			}
+
+			desiredQty := simulatedOrderSize(params, cfg.ParticipationCap)
+			if desiredQty <= 0 {
+				continue
+			}
+
+			exitDate := addDays(date, defaultHoldDays)
+			exit := entryOn(entries, exitDate)
+			if exit == nil || exit.Average <= 0 {
+				continue
+			}
+
+			entryImpact := engine.EstimateImpact(params, desiredQty, today.Average)
+			maxFillable := cfg.ParticipationCap * params.AvgDailyVolume
+			trade := SimulatedTrade{
+				Date:     date,
+				ExitDate: exitDate,
+				TypeID:   typeID,
+				Quantity: desiredQty,
+			}
+
+			if maxFillable > 0 && desiredQty > maxFillable {
+				trade.Rejected = true
+				report.Trades = append(report.Trades, trade)
+				continue
+			}
+
+			// Impact works against the trade on both legs: it pushes the
+			// entry price up and the exit price down, so the spread between
+			// them (not the raw impact cost) is what drives the day's P&L.
+			exitImpact := engine.EstimateImpact(params, desiredQty, exit.Average)
+			buyPrice := today.Average * (1 + entryImpact.RecommendedImpactPct/100)
+			sellPrice := exit.Average * (1 - exitImpact.RecommendedImpactPct/100)
+			profit := (sellPrice - buyPrice) * desiredQty
+
+			trade.FillPrice = buyPrice
+			trade.ExitPrice = sellPrice
+			trade.ImpactPct = entryImpact.RecommendedImpactPct
+			trade.GrossValue = buyPrice * desiredQty
+			trade.Profit = profit
+			report.Trades = append(report.Trades, trade)
+
+			dayPnL += profit
+			holdDaysSum += defaultHoldDays
+			holdCount++
+		}
+
+		balance += dayPnL
+		dailyPnL = append(dailyPnL, dayPnL)
+		report.Dates = append(report.Dates, date)
+	}
+
+	report.DailyPnL = dailyPnL
+	report.FinalBalance = balance
+
+	if len(dailyPnL) >= 2 {
+		mu := mean(dailyPnL)
+		sigma := math.Sqrt(variance(dailyPnL))
+		if sigma > 0 {
+			report.SharpeRatio = mu / sigma * math.Sqrt(365)
+		}
+		var downside []float64
+		for _, p := range dailyPnL {
+			if p < 0 {
+				downside = append(downside, p)
+			} else {
+				downside = append(downside, 0)
+			}
+		}
+		dd := math.Sqrt(meanOfSquares(downside))
+		if dd > 0 {
+			report.SortinoRatio = mu / dd * math.Sqrt(365)
+		}
+	}
+
+	var grossProfit, grossLoss float64
+	var wins, total int
+	cumulative, peak, maxDD := 0.0, 0.0, 0.0
+	for _, p := range dailyPnL {
+		total++
+		if p > 0 {
+			grossProfit += p
+			wins++
+		} else if p < 0 {
+			grossLoss += -p
+		}
+		cumulative += p
+		if cumulative > peak {
+			peak = cumulative
+		}
+		if d := peak - cumulative; d > maxDD {
+			maxDD = d
+		}
+	}
+	if grossLoss > 0 {
+		report.ProfitFactor = grossProfit / grossLoss
+	}
+	if total > 0 {
+		report.WinningRatio = float64(wins) / float64(total) * 100
+	}
+	report.MaxDrawdown = maxDD
+	if holdCount > 0 {
+		report.AvgHoldDays = holdDaysSum / float64(holdCount)
+	}
+
+	return report
+}
+
+// simulatedOrderSize derives a per-day order size from calibrated liquidity:
+// a conservative slice of average daily volume, capped by the participation
+// limit, standing in for whatever ScanParams would have sized the order at.
+func simulatedOrderSize(params engine.ImpactParams, participationCap float64) float64 {
+	if params.AvgDailyVolume <= 0 {
+		return 0
+	}
+	return params.AvgDailyVolume * math.Min(participationCap, engine.DefaultTWAPTargetPct)
+}
+
+// addDays offsets a YYYY-MM-DD date string by n days, returning "" if date
+// doesn't parse.
+func addDays(date string, n int) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, n).Format("2006-01-02")
+}
+
+func simulationDates(start, end string) []string {
+	startT, err1 := time.Parse("2006-01-02", start)
+	endT, err2 := time.Parse("2006-01-02", end)
+	if err1 != nil || err2 != nil || endT.Before(startT) {
+		return nil
+	}
+	var dates []string
+	for d := startT; !d.After(endT); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// entriesUpTo returns the trailing `days` history entries on or before date,
+// sorted chronologically.
+func entriesUpTo(entries []esi.HistoryEntry, date string, days int) []esi.HistoryEntry {
+	sorted := make([]esi.HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	var upTo []esi.HistoryEntry
+	for _, e := range sorted {
+		if e.Date <= date {
+			upTo = append(upTo, e)
+		}
+	}
+	if len(upTo) > days {
+		upTo = upTo[len(upTo)-days:]
+	}
+	return upTo
+}
+
+func entryOn(entries []esi.HistoryEntry, date string) *esi.HistoryEntry {
+	for i := range entries {
+		if entries[i].Date == date {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func mean(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v
+	}
+	return sum / float64(len(x))
+}
+
+func variance(x []float64) float64 {
+	if len(x) < 2 {
+		return 0
+	}
+	mu := mean(x)
+	var sq float64
+	for _, v := range x {
+		d := v - mu
+		sq += d * d
+	}
+	return sq / float64(len(x)-1)
+}
+
+func meanOfSquares(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum / float64(len(x))
+}
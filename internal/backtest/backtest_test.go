@@ -0,0 +1,86 @@
+package backtest
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+// historyWithGrowth builds 20 days of history (2026-01-01..2026-01-20)
+// starting at basePrice and compounding by each successive rate in growth
+// (cycled if shorter than 20), giving non-zero return variance so the
+// calibrated impact model has a real sigma to work with.
+func historyWithGrowth(basePrice float64, growth []float64) []esi.HistoryEntry {
+	entries := make([]esi.HistoryEntry, 20)
+	price := basePrice
+	for i := range entries {
+		if i > 0 {
+			price *= 1 + growth[i%len(growth)]
+		}
+		entries[i] = esi.HistoryEntry{
+			Date:    addDays("2026-01-01", i),
+			Average: price,
+			Highest: price * 1.01,
+			Lowest:  price * 0.99,
+			Volume:  100_000,
+		}
+	}
+	return entries
+}
+
+func TestSimulationDates(t *testing.T) {
+	dates := simulationDates("2026-01-01", "2026-01-03")
+	want := []string{"2026-01-01", "2026-01-02", "2026-01-03"}
+	if len(dates) != len(want) {
+		t.Fatalf("len(dates) = %d, want %d", len(dates), len(want))
+	}
+	for i, d := range want {
+		if dates[i] != d {
+			t.Errorf("dates[%d] = %q, want %q", i, dates[i], d)
+		}
+	}
+}
+
+func TestSimulationDates_InvalidRange(t *testing.T) {
+	if dates := simulationDates("2026-01-05", "2026-01-01"); dates != nil {
+		t.Errorf("simulationDates with end before start = %v, want nil", dates)
+	}
+}
+
+func TestRun_NoHistoryProducesEmptyReport(t *testing.T) {
+	cfg := Config{StartDate: "2026-01-01", EndDate: "2026-01-02", StartingBalance: 1_000_000}
+	report := Run(cfg, HistoryByType{})
+	if report.FinalBalance != report.StartingBalance {
+		t.Errorf("FinalBalance = %v, want starting balance %v (no trades)", report.FinalBalance, report.StartingBalance)
+	}
+}
+
+func TestRun_ProfitTracksPriceMovementNotRawImpactCost(t *testing.T) {
+	rising := HistoryByType{34: historyWithGrowth(100, []float64{0.02, 0.04, 0.01, 0.05, 0.03, 0.06})}
+	cfg := Config{StartDate: "2026-01-10", EndDate: "2026-01-12", StartingBalance: 1_000_000}
+
+	report := Run(cfg, rising)
+	if len(report.Trades) == 0 {
+		t.Fatalf("report.Trades is empty, want simulated round-trips")
+	}
+	for _, trade := range report.Trades {
+		if trade.Rejected {
+			continue
+		}
+		if trade.Profit <= 0 {
+			t.Errorf("trade %+v: Profit = %v, want > 0 on a rising-price window", trade, trade.Profit)
+		}
+	}
+	if report.FinalBalance <= report.StartingBalance {
+		t.Errorf("FinalBalance = %v, want > StartingBalance %v on a rising-price window", report.FinalBalance, report.StartingBalance)
+	}
+
+	falling := HistoryByType{34: historyWithGrowth(100, []float64{-0.02, -0.04, -0.01, -0.05, -0.03, -0.06})}
+	fallingReport := Run(cfg, falling)
+	if fallingReport.FinalBalance >= fallingReport.StartingBalance {
+		t.Errorf("FinalBalance = %v, want < StartingBalance %v on a falling-price window", fallingReport.FinalBalance, fallingReport.StartingBalance)
+	}
+	if fallingReport.MaxDrawdown <= 0 {
+		t.Errorf("MaxDrawdown = %v, want > 0 once a trade can actually lose money", fallingReport.MaxDrawdown)
+	}
+}
@@ -0,0 +1,227 @@
+package corp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"eve-flipper/internal/alerts"
+)
+
+// MinWalletDivision and MaxWalletDivision bound the standard EVE
+// corporation wallet division range.
+const (
+	MinWalletDivision = 1
+	MaxWalletDivision = 7
+)
+
+// allocationSumEpsilon tolerates float rounding when operators enter
+// targets as e.g. 0.4 + 0.2 + 0.2 + 0.2.
+const allocationSumEpsilon = 0.001
+
+// DefaultRebalanceTolerancePct is how many percentage points a division's
+// actual share may drift from its target before it's considered worth
+// moving ISK over.
+const DefaultRebalanceTolerancePct = 2.0
+
+// DashboardOptions configures BuildDashboardWithOptions. The zero value
+// reproduces BuildDashboard's behavior (no allocation targets, so
+// CorpDashboard.AllocationSummary is left nil).
+type DashboardOptions struct {
+	// AllocationTargets maps wallet division (1-7) to its target share of
+	// TotalBalance, expressed as a fraction that must sum to 1.0 (see
+	// NormalizeAllocationTargets). Left empty, allocation tracking is
+	// skipped entirely.
+	AllocationTargets map[int]float64
+	// RebalanceTolerancePct is the drift (in percentage points) a division
+	// may sit away from its target before a suggested move is generated
+	// for it. Defaults to DefaultRebalanceTolerancePct when <= 0.
+	RebalanceTolerancePct float64
+
+	// PriceProvider, when set, values mining output and open market orders
+	// at real regional market price instead of MiningSummary's flat
+	// per-unit estimate and MarketSummary's listing price. Left nil, both
+	// summaries fall back to their pre-pricing behavior. Pass a
+	// TTLPriceCache wrapping the real provider to avoid re-fetching on
+	// every dashboard rebuild.
+	PriceProvider PriceProvider
+	// HomeRegionID is the region mining output and open market orders are
+	// priced against (the corp's home trade hub). Ignored when
+	// PriceProvider is nil.
+	HomeRegionID int64
+
+	// BudgetEnvelopes maps a refTypeCategory (e.g. "bounties", "market",
+	// "taxes", "srp") to its monthly ISK budget. Left empty,
+	// CorpDashboard.BudgetStatus is left nil.
+	BudgetEnvelopes map[string]float64
+	// BudgetWarnPct and BudgetCriticalPct are the PercentUsed thresholds at
+	// which a category's BudgetStatus fires a warning/critical alert via
+	// BudgetAlerter. Default to DefaultBudgetWarnPct/DefaultBudgetCriticalPct
+	// when <= 0.
+	BudgetWarnPct     float64
+	BudgetCriticalPct float64
+	// BudgetAlerter, when set, receives budget_warning/budget_critical
+	// alerts as each category's spend crosses BudgetWarnPct/BudgetCriticalPct,
+	// deduplicated by its own cooldown so a rebuild doesn't re-fire the same
+	// crossing every time. Left nil, BudgetStatus is still computed but no
+	// alerts are raised.
+	BudgetAlerter *alerts.Alerter
+}
+
+// DivisionAllocation compares one wallet division's actual share of the
+// corp's total balance against its declared target.
+type DivisionAllocation struct {
+	Division   int     `json:"division"`
+	Balance    float64 `json:"balance"`
+	CurrentPct float64 `json:"current_pct"`
+	TargetPct  float64 `json:"target_pct"`
+	DriftPct   float64 `json:"drift_pct"` // current - target; positive = overweight
+}
+
+// AllocationMove is one suggested ISK transfer in a rebalance plan.
+type AllocationMove struct {
+	FromDivision int     `json:"from_division"`
+	ToDivision   int     `json:"to_division"`
+	Amount       float64 `json:"amount"`
+}
+
+// AllocationSummary is the treasury-management view of wallet-division
+// targets: current vs. target allocation per division, plus a greedily
+// computed set of moves that would bring every division within tolerance.
+type AllocationSummary struct {
+	Divisions      []DivisionAllocation `json:"divisions"`
+	SuggestedMoves []AllocationMove     `json:"suggested_moves"`
+	Warnings       []string             `json:"warnings,omitempty"`
+}
+
+// NormalizeAllocationTargets validates a caller-supplied allocation target
+// map: targets must sum to 1.0 within allocationSumEpsilon, returned as an
+// error since a misconfigured treasury policy shouldn't silently degrade.
+// Division IDs outside the standard 1-7 range are reported as warnings
+// instead, since they're harmless to compute against (the division's
+// actual balance is simply 0) and may be intentional for corps that plan
+// to open an additional division.
+func NormalizeAllocationTargets(targets map[int]float64) (map[int]float64, []string, error) {
+	if len(targets) == 0 {
+		return nil, nil, nil
+	}
+
+	sum := 0.0
+	var warnings []string
+	normalized := make(map[int]float64, len(targets))
+	for division, pct := range targets {
+		sum += pct
+		normalized[division] = pct
+		if division < MinWalletDivision || division > MaxWalletDivision {
+			warnings = append(warnings, fmt.Sprintf("allocation target references unknown wallet division %d", division))
+		}
+	}
+
+	if math.Abs(sum-1.0) > allocationSumEpsilon {
+		return nil, warnings, fmt.Errorf("allocation targets must sum to 1.0, got %.4f", sum)
+	}
+	return normalized, warnings, nil
+}
+
+// computeAllocationSummary builds the AllocationSummary for BuildDashboardWithOptions.
+// Returns nil when no targets are configured.
+func computeAllocationSummary(wallets []CorpWalletDivision, targets map[int]float64, tolerancePct, totalBalance float64) *AllocationSummary {
+	if len(targets) == 0 {
+		return nil
+	}
+	if tolerancePct <= 0 {
+		tolerancePct = DefaultRebalanceTolerancePct
+	}
+
+	normalized, warnings, err := NormalizeAllocationTargets(targets)
+	if err != nil {
+		return &AllocationSummary{Warnings: append(warnings, err.Error())}
+	}
+
+	balances := make(map[int]float64, len(wallets))
+	for _, w := range wallets {
+		balances[w.Division] = w.Balance
+	}
+
+	divisionIDs := make([]int, 0, len(normalized))
+	for division := range normalized {
+		divisionIDs = append(divisionIDs, division)
+	}
+	sort.Ints(divisionIDs)
+
+	allocations := make([]DivisionAllocation, 0, len(divisionIDs))
+	for _, division := range divisionIDs {
+		balance := balances[division]
+		currentPct := 0.0
+		if totalBalance > 0 {
+			currentPct = balance / totalBalance * 100
+		}
+		targetPct := normalized[division] * 100
+		allocations = append(allocations, DivisionAllocation{
+			Division:   division,
+			Balance:    balance,
+			CurrentPct: currentPct,
+			TargetPct:  targetPct,
+			DriftPct:   currentPct - targetPct,
+		})
+	}
+
+	return &AllocationSummary{
+		Divisions:      allocations,
+		SuggestedMoves: suggestRebalanceMoves(allocations, totalBalance, tolerancePct),
+		Warnings:       warnings,
+	}
+}
+
+// suggestRebalanceMoves greedily pairs the most overweight division's
+// surplus with the most underweight division's deficit, transferring the
+// smaller of the two amounts, and repeats until either the surplus or the
+// deficit side is used up. Divisions already within tolerancePct are neither
+// sources nor destinations, so a surplus whose matching shortfall sits in
+// such divisions is left in place. This isn't the optimal transportation-problem
+// solution, but for a handful of wallet divisions a minimal move count
+// matters less than the plan being easy to read and act on.
+func suggestRebalanceMoves(allocations []DivisionAllocation, totalBalance, tolerancePct float64) []AllocationMove {
+	if totalBalance <= 0 {
+		return nil
+	}
+
+	type bucket struct {
+		division int
+		amount   float64 // ISK surplus or deficit magnitude
+	}
+	var surplus, deficit []bucket
+	for _, a := range allocations {
+		iskDrift := a.DriftPct / 100 * totalBalance
+		if a.DriftPct > tolerancePct {
+			surplus = append(surplus, bucket{division: a.Division, amount: iskDrift})
+		} else if a.DriftPct < -tolerancePct {
+			deficit = append(deficit, bucket{division: a.Division, amount: -iskDrift})
+		}
+	}
+	sort.Slice(surplus, func(i, j int) bool { return surplus[i].amount > surplus[j].amount })
+	sort.Slice(deficit, func(i, j int) bool { return deficit[i].amount > deficit[j].amount })
+
+	var moves []AllocationMove
+	i, j := 0, 0
+	for i < len(surplus) && j < len(deficit) {
+		amount := surplus[i].amount
+		if deficit[j].amount < amount {
+			amount = deficit[j].amount
+		}
+		moves = append(moves, AllocationMove{
+			FromDivision: surplus[i].division,
+			ToDivision:   deficit[j].division,
+			Amount:       amount,
+		})
+		surplus[i].amount -= amount
+		deficit[j].amount -= amount
+		if surplus[i].amount < 1 {
+			i++
+		}
+		if deficit[j].amount < 1 {
+			j++
+		}
+	}
+	return moves
+}
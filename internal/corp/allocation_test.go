@@ -0,0 +1,63 @@
+package corp
+
+import "testing"
+
+func TestSuggestRebalanceMoves_PairsOverweightWithUnderweight(t *testing.T) {
+	allocations := []DivisionAllocation{
+		{Division: 1, DriftPct: 10}, // 10% overweight
+		{Division: 2, DriftPct: -6}, // 6% underweight
+		{Division: 3, DriftPct: 0.5},
+	}
+	moves := suggestRebalanceMoves(allocations, 1_000_000, DefaultRebalanceTolerancePct)
+	if len(moves) != 1 {
+		t.Fatalf("moves = %+v, want exactly one transfer", moves)
+	}
+	m := moves[0]
+	if m.FromDivision != 1 || m.ToDivision != 2 {
+		t.Errorf("move = %+v, want division 1 -> 2", m)
+	}
+	// Division 2's 6% deficit (60,000 ISK) is smaller than division 1's 10%
+	// surplus (100,000 ISK), so the transfer is capped at the deficit.
+	if want := 60_000.0; m.Amount != want {
+		t.Errorf("Amount = %v, want %v", m.Amount, want)
+	}
+}
+
+func TestSuggestRebalanceMoves_WithinToleranceProducesNoMoves(t *testing.T) {
+	allocations := []DivisionAllocation{
+		{Division: 1, DriftPct: 1.5},
+		{Division: 2, DriftPct: -1.5},
+	}
+	if moves := suggestRebalanceMoves(allocations, 1_000_000, DefaultRebalanceTolerancePct); moves != nil {
+		t.Errorf("moves = %+v, want nil (both within tolerance)", moves)
+	}
+}
+
+func TestSuggestRebalanceMoves_ZeroBalanceReturnsNil(t *testing.T) {
+	allocations := []DivisionAllocation{{Division: 1, DriftPct: 50}}
+	if moves := suggestRebalanceMoves(allocations, 0, DefaultRebalanceTolerancePct); moves != nil {
+		t.Errorf("moves = %+v, want nil with zero totalBalance", moves)
+	}
+}
+
+func TestSuggestRebalanceMoves_MultipleDeficitsSplitASingleSurplus(t *testing.T) {
+	allocations := []DivisionAllocation{
+		{Division: 1, DriftPct: 10}, // 100,000 ISK surplus
+		{Division: 2, DriftPct: -7}, // 70,000 ISK deficit
+		{Division: 3, DriftPct: -4}, // 40,000 ISK deficit
+	}
+	moves := suggestRebalanceMoves(allocations, 1_000_000, DefaultRebalanceTolerancePct)
+	if len(moves) != 2 {
+		t.Fatalf("moves = %+v, want two transfers (one surplus split across two deficits)", moves)
+	}
+	var total float64
+	for _, m := range moves {
+		if m.FromDivision != 1 {
+			t.Errorf("move = %+v, want every leg to originate from division 1", m)
+		}
+		total += m.Amount
+	}
+	if want := 100_000.0; total != want {
+		t.Errorf("total moved = %v, want %v (can't move more than the surplus)", total, want)
+	}
+}
@@ -0,0 +1,145 @@
+package corp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"eve-flipper/internal/alerts"
+	"eve-flipper/internal/money"
+)
+
+// DefaultBudgetWarnPct and DefaultBudgetCriticalPct are the PercentUsed
+// thresholds at which a BudgetStatus fires a warning or critical alert,
+// used when DashboardOptions doesn't override them.
+const (
+	DefaultBudgetWarnPct     = 80.0
+	DefaultBudgetCriticalPct = 100.0
+)
+
+// BudgetStatus is one expense category's spend against its configured
+// monthly envelope for the current, partially-elapsed month.
+type BudgetStatus struct {
+	Category    string  `json:"category"`
+	Label       string  `json:"label"`
+	Spent       float64 `json:"spent"`
+	Budget      float64 `json:"budget"`
+	PercentUsed float64 `json:"percent_used"`
+	// Projection linearly extrapolates Spent from the elapsed fraction of
+	// the month to a full-month total, so a director sees "on pace to blow
+	// the budget" before the month is actually over.
+	Projection float64 `json:"projection"`
+}
+
+// computeBudgetStatus totals current-month expenses per refTypeCategory and
+// compares each against its configured monthly envelope. Categories with no
+// configured budget are skipped; categories with a budget but zero spend
+// this month are still reported at 0%, since a director watching the list
+// shouldn't have to wonder whether the category was simply omitted.
+func computeBudgetStatus(journal []CorpJournalEntry, budgets map[string]float64, now time.Time) []BudgetStatus {
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	spend := make(map[string]decimal.Decimal)
+	for _, e := range journal {
+		if e.Date[:10] < monthStart {
+			continue
+		}
+		amount := money.FromFloat(e.Amount)
+		if !amount.IsNegative() {
+			continue
+		}
+		cat := refTypeCategory[e.RefType]
+		spend[cat] = spend[cat].Add(amount.Abs())
+	}
+
+	daysElapsed := now.Day()
+	daysInMonth := daysInMonth(now)
+
+	categories := make([]string, 0, len(budgets))
+	for cat := range budgets {
+		categories = append(categories, cat)
+	}
+	sort.Strings(categories)
+
+	statuses := make([]BudgetStatus, 0, len(categories))
+	for _, cat := range categories {
+		budget := budgets[cat]
+		spent := money.ToFloat(spend[cat])
+
+		var pctUsed float64
+		if budget > 0 {
+			pctUsed = spent / budget * 100
+		}
+		projection := spent
+		if daysElapsed > 0 {
+			projection = spent * float64(daysInMonth) / float64(daysElapsed)
+		}
+
+		label := categoryLabels[cat]
+		if label == "" {
+			label = cat
+		}
+		statuses = append(statuses, BudgetStatus{
+			Category:    cat,
+			Label:       label,
+			Spent:       spent,
+			Budget:      budget,
+			PercentUsed: pctUsed,
+			Projection:  projection,
+		})
+	}
+	return statuses
+}
+
+// daysInMonth returns the number of days in t's month.
+func daysInMonth(t time.Time) int {
+	firstOfNextMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	return firstOfNextMonth.AddDate(0, 0, -1).Day()
+}
+
+// raiseBudgetAlerts fires (and clears) alerts.Alerter alerts for each
+// BudgetStatus that crosses warnPct/criticalPct. The watchlist scan flow
+// (internal/api.CheckWatchlistAlerts/SendAlert) tracks and dispatches its
+// own alerts directly against the db package instead of going through this
+// machinery. Alerter's cooldown handles deduplication: a category sitting
+// above a threshold across several same-day dashboard rebuilds only
+// re-dispatches once the cooldown configured on the Alerter elapses.
+func raiseBudgetAlerts(alerter *alerts.Alerter, statuses []BudgetStatus, warnPct, criticalPct float64) {
+	if alerter == nil {
+		return
+	}
+	if warnPct <= 0 {
+		warnPct = DefaultBudgetWarnPct
+	}
+	if criticalPct <= 0 {
+		criticalPct = DefaultBudgetCriticalPct
+	}
+
+	for _, s := range statuses {
+		context := map[string]any{
+			"category":     s.Category,
+			"spent":        s.Spent,
+			"budget":       s.Budget,
+			"percent_used": s.PercentUsed,
+			"projection":   s.Projection,
+		}
+		switch {
+		case s.PercentUsed >= criticalPct:
+			alerter.Resolve("budget_warning", s.Category, fmt.Sprintf("%s spend escalated past %.0f%%", s.Label, criticalPct))
+			alerter.Raise("budget_critical", s.Category, alerts.SeverityCritical,
+				fmt.Sprintf("%s spend is at %.0f%% of its monthly budget (%.2f / %.2f ISK)", s.Label, s.PercentUsed, s.Spent, s.Budget), context)
+		case s.PercentUsed >= warnPct:
+			alerter.Resolve("budget_critical", s.Category, fmt.Sprintf("%s spend dropped back below %.0f%%", s.Label, criticalPct))
+			alerter.Raise("budget_warning", s.Category, alerts.SeverityWarning,
+				fmt.Sprintf("%s spend is at %.0f%% of its monthly budget (%.2f / %.2f ISK)", s.Label, s.PercentUsed, s.Spent, s.Budget), context)
+		default:
+			alerter.Resolve("budget_critical", s.Category, fmt.Sprintf("%s spend dropped back below %.0f%%", s.Label, criticalPct))
+			alerter.Resolve("budget_warning", s.Category, fmt.Sprintf("%s spend dropped back below %.0f%%", s.Label, warnPct))
+		}
+	}
+}
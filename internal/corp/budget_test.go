@@ -0,0 +1,67 @@
+package corp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeBudgetStatus_TotalsCurrentMonthExpensesByCategory(t *testing.T) {
+	now := time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC) // 15 of 31 days elapsed
+	journal := []CorpJournalEntry{
+		{Date: "2026-07-01", Amount: -400_000, RefType: "market_transaction"},
+		{Date: "2026-07-10", Amount: -600_000, RefType: "brokers_fee"},
+		{Date: "2026-06-30", Amount: -999_999, RefType: "market_transaction"},  // last month, excluded
+		{Date: "2026-07-05", Amount: 2_000_000, RefType: "market_transaction"}, // income, not spend
+	}
+	budgets := map[string]float64{"market": 2_000_000}
+
+	statuses := computeBudgetStatus(journal, budgets, now)
+	if len(statuses) != 1 {
+		t.Fatalf("statuses = %+v, want exactly one category", statuses)
+	}
+	s := statuses[0]
+	if s.Category != "market" {
+		t.Errorf("Category = %q, want market", s.Category)
+	}
+	if want := 1_000_000.0; s.Spent != want {
+		t.Errorf("Spent = %v, want %v (only this-month debits, income excluded)", s.Spent, want)
+	}
+	if want := 50.0; s.PercentUsed != want {
+		t.Errorf("PercentUsed = %v, want %v", s.PercentUsed, want)
+	}
+	// Projection linearly extrapolates the elapsed fraction (15/31) to a
+	// full month: 1,000,000 * 31/15.
+	if want := 1_000_000.0 * 31 / 15; s.Projection != want {
+		t.Errorf("Projection = %v, want %v", s.Projection, want)
+	}
+}
+
+func TestComputeBudgetStatus_UnspentBudgetedCategoryReportsZero(t *testing.T) {
+	now := time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC)
+	statuses := computeBudgetStatus(nil, map[string]float64{"srp": 500_000}, now)
+	if len(statuses) != 1 || statuses[0].Spent != 0 || statuses[0].PercentUsed != 0 {
+		t.Fatalf("statuses = %+v, want one zero-spend entry for a configured-but-unused category", statuses)
+	}
+}
+
+func TestComputeBudgetStatus_NoBudgetsReturnsNil(t *testing.T) {
+	if statuses := computeBudgetStatus(nil, nil, time.Now()); statuses != nil {
+		t.Errorf("statuses = %+v, want nil with no configured budgets", statuses)
+	}
+}
+
+func TestDaysInMonth(t *testing.T) {
+	cases := []struct {
+		t    time.Time
+		want int
+	}{
+		{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 28}, // 2026 is not a leap year
+		{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 29}, // 2024 is a leap year
+		{time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), 31},
+	}
+	for _, c := range cases {
+		if got := daysInMonth(c.t); got != c.want {
+			t.Errorf("daysInMonth(%s) = %d, want %d", c.t.Format("2006-01"), got, c.want)
+		}
+	}
+}
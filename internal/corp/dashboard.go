@@ -5,10 +5,22 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
+
+	"eve-flipper/internal/money"
 )
 
 // BuildDashboard aggregates raw data from a CorpDataProvider into a CorpDashboard.
 func BuildDashboard(provider CorpDataProvider) (*CorpDashboard, error) {
+	return BuildDashboardWithOptions(provider, DashboardOptions{})
+}
+
+// BuildDashboardWithOptions is BuildDashboard with treasury-management
+// extensions: when opt.AllocationTargets is set, it also populates
+// CorpDashboard.AllocationSummary with each division's drift from target
+// and a suggested rebalance plan.
+func BuildDashboardWithOptions(provider CorpDataProvider, opt DashboardOptions) (*CorpDashboard, error) {
 	info := provider.GetInfo()
 	isDemo := provider.IsDemo()
 
@@ -17,10 +29,11 @@ func BuildDashboard(provider CorpDataProvider) (*CorpDashboard, error) {
 		return nil, err
 	}
 
-	totalBalance := 0.0
+	totalBalanceD := decimal.Zero
 	for _, w := range wallets {
-		totalBalance += w.Balance
+		totalBalanceD = totalBalanceD.Add(money.FromFloat(w.Balance))
 	}
+	totalBalance := money.ToFloat(totalBalanceD)
 
 	// Fetch journal for master wallet (division 1) for financial overview
 	journal, _ := provider.GetJournal(1, 90)
@@ -34,24 +47,29 @@ func BuildDashboard(provider CorpDataProvider) (*CorpDashboard, error) {
 	day30ago := now.AddDate(0, 0, -30).Format("2006-01-02")
 
 	// ---- Revenue / Expenses ----
-	var rev7, exp7, rev30, exp30 float64
+	rev7D, exp7D, rev30D, exp30D := decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
 	for _, e := range journal {
 		dateOnly := e.Date[:10]
+		amount := money.FromFloat(e.Amount)
 		if dateOnly >= day30ago {
-			if e.Amount > 0 {
-				rev30 += e.Amount
+			if amount.IsPositive() {
+				rev30D = rev30D.Add(amount)
 			} else {
-				exp30 += e.Amount
+				exp30D = exp30D.Add(amount)
 			}
 		}
 		if dateOnly >= day7ago {
-			if e.Amount > 0 {
-				rev7 += e.Amount
+			if amount.IsPositive() {
+				rev7D = rev7D.Add(amount)
 			} else {
-				exp7 += e.Amount
+				exp7D = exp7D.Add(amount)
 			}
 		}
 	}
+	rev7, exp7 := money.ToFloat(rev7D), money.ToFloat(exp7D)
+	rev30, exp30 := money.ToFloat(rev30D), money.ToFloat(exp30D)
+	netIncome7D := rev7D.Add(exp7D)
+	netIncome30D := rev30D.Add(exp30D)
 
 	// ---- Income by source ----
 	incomeBySource := computeIncomeBySource(journal, day30ago)
@@ -69,29 +87,38 @@ func BuildDashboard(provider CorpDataProvider) (*CorpDashboard, error) {
 	industrySummary := computeIndustrySummary(industryJobs, now)
 
 	// ---- Mining Summary ----
-	miningSummary := computeMiningSummary(miningLedger)
+	miningSummary := computeMiningSummary(miningLedger, opt.PriceProvider, opt.HomeRegionID)
 
 	// ---- Market Summary ----
-	marketSummary := computeMarketSummary(orders)
+	marketSummary := computeMarketSummary(orders, opt.PriceProvider, opt.HomeRegionID)
+
+	// ---- Allocation Summary (treasury rebalancing) ----
+	allocationSummary := computeAllocationSummary(wallets, opt.AllocationTargets, opt.RebalanceTolerancePct, totalBalance)
+
+	// ---- Budget Status (expense envelopes) ----
+	budgetStatus := computeBudgetStatus(journal, opt.BudgetEnvelopes, now)
+	raiseBudgetAlerts(opt.BudgetAlerter, budgetStatus, opt.BudgetWarnPct, opt.BudgetCriticalPct)
 
 	return &CorpDashboard{
-		Info:            info,
-		IsDemo:          isDemo,
-		Wallets:         wallets,
-		TotalBalance:    totalBalance,
-		Revenue30d:      rev30,
-		Expenses30d:     exp30,
-		NetIncome30d:    rev30 + exp30,
-		Revenue7d:       rev7,
-		Expenses7d:      exp7,
-		NetIncome7d:     rev7 + exp7,
-		IncomeBySource:  incomeBySource,
-		DailyPnL:        dailyPnL,
-		TopContributors: topContributors,
-		MemberSummary:   memberSummary,
-		IndustrySummary: industrySummary,
-		MiningSummary:   miningSummary,
-		MarketSummary:   marketSummary,
+		Info:              info,
+		IsDemo:            isDemo,
+		Wallets:           wallets,
+		TotalBalance:      totalBalance,
+		Revenue30d:        rev30,
+		Expenses30d:       exp30,
+		NetIncome30d:      money.ToFloat(netIncome30D),
+		Revenue7d:         rev7,
+		Expenses7d:        exp7,
+		NetIncome7d:       money.ToFloat(netIncome7D),
+		IncomeBySource:    incomeBySource,
+		DailyPnL:          dailyPnL,
+		TopContributors:   topContributors,
+		MemberSummary:     memberSummary,
+		IndustrySummary:   industrySummary,
+		MiningSummary:     miningSummary,
+		MarketSummary:     marketSummary,
+		AllocationSummary: allocationSummary,
+		BudgetStatus:      budgetStatus,
 	}, nil
 }
 
@@ -101,22 +128,22 @@ func BuildDashboard(provider CorpDataProvider) (*CorpDashboard, error) {
 
 // refTypeCategory maps ESI ref_types to dashboard categories.
 var refTypeCategory = map[string]string{
-	"bounty_prizes":                   "bounties",
-	"agent_mission_reward":            "bounties",
-	"market_transaction":              "market",
-	"market_escrow":                   "market",
-	"brokers_fee":                     "market",
-	"transaction_tax":                 "taxes",
-	"planetary_interaction":           "pi",
-	"industry_job_tax":                "industry",
-	"reprocessing_tax":                "industry",
-	"insurance":                       "srp",
-	"moon_mining_extraction_tax":      "mining",
-	"contract_price":                  "market",
-	"player_donation":                 "other",
-	"corporation_account_withdrawal":  "other",
-	"office_rental_fee":               "taxes",
-	"jump_clone_activation_fee":       "taxes",
+	"bounty_prizes":                  "bounties",
+	"agent_mission_reward":           "bounties",
+	"market_transaction":             "market",
+	"market_escrow":                  "market",
+	"brokers_fee":                    "market",
+	"transaction_tax":                "taxes",
+	"planetary_interaction":          "pi",
+	"industry_job_tax":               "industry",
+	"reprocessing_tax":               "industry",
+	"insurance":                      "srp",
+	"moon_mining_extraction_tax":     "mining",
+	"contract_price":                 "market",
+	"player_donation":                "other",
+	"corporation_account_withdrawal": "other",
+	"office_rental_fee":              "taxes",
+	"jump_clone_activation_fee":      "taxes",
 }
 
 var categoryLabels = map[string]string{
@@ -131,29 +158,40 @@ var categoryLabels = map[string]string{
 }
 
 func computeIncomeBySource(journal []CorpJournalEntry, since string) []IncomeSource {
-	totals := make(map[string]float64)
-	totalIncome := 0.0
+	return computeIncomeBySourceInRange(journal, since, "")
+}
+
+// computeIncomeBySourceInRange is computeIncomeBySource bounded on both
+// ends: entries on or after since and, when until is non-empty, strictly
+// before until. An empty until leaves the range open-ended, reproducing
+// computeIncomeBySource's behavior. It backs BuildPeriodReport's need to
+// total a single prior window rather than everything since some date.
+func computeIncomeBySourceInRange(journal []CorpJournalEntry, since, until string) []IncomeSource {
+	totals := make(map[string]decimal.Decimal)
+	totalIncome := decimal.Zero
 
 	for _, e := range journal {
-		if e.Date[:10] < since {
+		dateOnly := e.Date[:10]
+		if dateOnly < since {
+			continue
+		}
+		if until != "" && dateOnly >= until {
 			continue
 		}
 		cat := refTypeCategory[e.RefType]
 		if cat == "" {
 			cat = "other"
 		}
-		totals[cat] += e.Amount
-		if e.Amount > 0 {
-			totalIncome += e.Amount
+		amount := money.FromFloat(e.Amount)
+		totals[cat] = totals[cat].Add(amount)
+		if amount.IsPositive() {
+			totalIncome = totalIncome.Add(amount)
 		}
 	}
 
 	var sources []IncomeSource
 	for cat, amount := range totals {
-		pct := 0.0
-		if totalIncome > 0 {
-			pct = math.Abs(amount) / totalIncome * 100
-		}
+		pct := money.PercentOf(amount.Abs(), totalIncome)
 		label := categoryLabels[cat]
 		if label == "" {
 			label = cat
@@ -161,8 +199,8 @@ func computeIncomeBySource(journal []CorpJournalEntry, since string) []IncomeSou
 		sources = append(sources, IncomeSource{
 			Category: cat,
 			Label:    label,
-			Amount:   amount,
-			Percent:  math.Round(pct*10) / 10,
+			Amount:   money.ToFloat(amount),
+			Percent:  money.ToFloat(pct),
 		})
 	}
 
@@ -179,12 +217,21 @@ func computeIncomeBySource(journal []CorpJournalEntry, since string) []IncomeSou
 // ============================================================
 
 func computeDailyPnL(journal []CorpJournalEntry, days int, now time.Time) []DailyPnLEntry {
-	dailyMap := make(map[string]*DailyPnLEntry)
+	type dayAgg struct {
+		date         string
+		revenue      decimal.Decimal
+		expenses     decimal.Decimal
+		transactions int
+	}
 
-	// Pre-populate all days
+	// Pre-populate all days, in order, so the decimal running total below
+	// doesn't need a second pass over a sorted slice.
+	order := make([]string, 0, days)
+	dailyMap := make(map[string]*dayAgg, days)
 	for d := days - 1; d >= 0; d-- {
 		dateStr := now.AddDate(0, 0, -d).Format("2006-01-02")
-		dailyMap[dateStr] = &DailyPnLEntry{Date: dateStr}
+		dailyMap[dateStr] = &dayAgg{date: dateStr}
+		order = append(order, dateStr)
 	}
 
 	for _, e := range journal {
@@ -193,28 +240,31 @@ func computeDailyPnL(journal []CorpJournalEntry, days int, now time.Time) []Dail
 		if !ok {
 			continue
 		}
-		if e.Amount > 0 {
-			entry.Revenue += e.Amount
+		amount := money.FromFloat(e.Amount)
+		if amount.IsPositive() {
+			entry.revenue = entry.revenue.Add(amount)
 		} else {
-			entry.Expenses += e.Amount
+			entry.expenses = entry.expenses.Add(amount)
 		}
-		entry.NetIncome = entry.Revenue + entry.Expenses
-		entry.Transactions++
+		entry.transactions++
 	}
 
-	// Convert to sorted slice and compute cumulative
+	// Convert to slice, computing the cumulative total in Decimal the
+	// whole way through so it never round-trips through float64.
 	result := make([]DailyPnLEntry, 0, days)
-	for d := days - 1; d >= 0; d-- {
-		dateStr := now.AddDate(0, 0, -d).Format("2006-01-02")
-		if entry, ok := dailyMap[dateStr]; ok {
-			result = append(result, *entry)
-		}
-	}
-
-	cumul := 0.0
-	for i := range result {
-		cumul += result[i].NetIncome
-		result[i].Cumulative = cumul
+	cumul := decimal.Zero
+	for _, dateStr := range order {
+		entry := dailyMap[dateStr]
+		netIncome := entry.revenue.Add(entry.expenses)
+		cumul = cumul.Add(netIncome)
+		result = append(result, DailyPnLEntry{
+			Date:         entry.date,
+			Revenue:      money.ToFloat(entry.revenue),
+			Expenses:     money.ToFloat(entry.expenses),
+			NetIncome:    money.ToFloat(netIncome),
+			Cumulative:   money.ToFloat(cumul),
+			Transactions: entry.transactions,
+		})
 	}
 
 	return result
@@ -225,18 +275,28 @@ func computeDailyPnL(journal []CorpJournalEntry, days int, now time.Time) []Dail
 // ============================================================
 
 func computeTopContributors(journal []CorpJournalEntry, members []CorpMember, since string) []MemberContribution {
+	return computeTopContributorsInRange(journal, members, since, "")
+}
+
+// computeTopContributorsInRange is computeTopContributors bounded on both
+// ends, mirroring computeIncomeBySourceInRange; an empty until reproduces
+// computeTopContributors. BuildPeriodReport uses this to rank contributors
+// within a single prior window instead of everything since some date.
+func computeTopContributorsInRange(journal []CorpJournalEntry, members []CorpMember, since, until string) []MemberContribution {
 	// Sum positive amounts by first_party_id, track dominant ref_type per contributor
-	contrib := make(map[int64]float64)
-	contribRefTypes := make(map[int64]map[string]float64) // charID -> refType -> total ISK
+	contrib := make(map[int64]decimal.Decimal)
+	contribRefTypes := make(map[int64]map[string]decimal.Decimal) // charID -> refType -> total ISK
 	for _, e := range journal {
-		if e.Date[:10] < since || e.Amount <= 0 {
+		dateOnly := e.Date[:10]
+		if dateOnly < since || (until != "" && dateOnly >= until) || e.Amount <= 0 {
 			continue
 		}
-		contrib[e.FirstPartyID] += e.Amount
+		amount := money.FromFloat(e.Amount)
+		contrib[e.FirstPartyID] = contrib[e.FirstPartyID].Add(amount)
 		if contribRefTypes[e.FirstPartyID] == nil {
-			contribRefTypes[e.FirstPartyID] = make(map[string]float64)
+			contribRefTypes[e.FirstPartyID] = make(map[string]decimal.Decimal)
 		}
-		contribRefTypes[e.FirstPartyID][e.RefType] += e.Amount
+		contribRefTypes[e.FirstPartyID][e.RefType] = contribRefTypes[e.FirstPartyID][e.RefType].Add(amount)
 	}
 
 	// Build name map from members + journal party names as fallback
@@ -272,7 +332,7 @@ func computeTopContributors(journal []CorpJournalEntry, members []CorpMember, si
 		result = append(result, MemberContribution{
 			CharacterID: charID,
 			Name:        name,
-			TotalISK:    total,
+			TotalISK:    money.ToFloat(total),
 			Category:    category,
 			IsOnline:    onlineMap[charID],
 		})
@@ -292,12 +352,12 @@ func computeTopContributors(journal []CorpJournalEntry, members []CorpMember, si
 
 // categorizeMember determines a member's primary economic role based on their
 // dominant journal ref_type by ISK volume.
-func categorizeMember(refTypes map[string]float64) string {
+func categorizeMember(refTypes map[string]decimal.Decimal) string {
 	if len(refTypes) == 0 {
 		return "other"
 	}
 
-	categoryScores := make(map[string]float64)
+	categoryScores := make(map[string]decimal.Decimal)
 	for refType, amount := range refTypes {
 		cat := refTypeCategory[refType]
 		if cat == "" {
@@ -305,23 +365,23 @@ func categorizeMember(refTypes map[string]float64) string {
 		}
 		switch cat {
 		case "bounties":
-			categoryScores["ratter"] += amount
+			categoryScores["ratter"] = categoryScores["ratter"].Add(amount)
 		case "mining":
-			categoryScores["miner"] += amount
+			categoryScores["miner"] = categoryScores["miner"].Add(amount)
 		case "market":
-			categoryScores["trader"] += amount
+			categoryScores["trader"] = categoryScores["trader"].Add(amount)
 		case "industry":
-			categoryScores["industrialist"] += amount
+			categoryScores["industrialist"] = categoryScores["industrialist"].Add(amount)
 		default:
-			categoryScores["other"] += amount
+			categoryScores["other"] = categoryScores["other"].Add(amount)
 		}
 	}
 
 	// Pick the category with the highest ISK
 	best := "other"
-	bestAmount := 0.0
+	bestAmount := decimal.Zero
 	for cat, amount := range categoryScores {
-		if amount > bestAmount {
+		if amount.GreaterThan(bestAmount) {
 			best = cat
 			bestAmount = amount
 		}
@@ -442,7 +502,7 @@ func computeIndustrySummary(jobs []CorpIndustryJob, now time.Time) IndustrySumma
 // Mining Summary
 // ============================================================
 
-func computeMiningSummary(entries []CorpMiningEntry) MiningSummary {
+func computeMiningSummary(entries []CorpMiningEntry, prices PriceProvider, regionID int64) MiningSummary {
 	s := MiningSummary{}
 
 	minerSet := make(map[int64]bool)
@@ -465,19 +525,35 @@ func computeMiningSummary(entries []CorpMiningEntry) MiningSummary {
 
 	s.ActiveMiners = len(minerSet)
 
+	estimatedISK := decimal.Zero
 	for _, oe := range oreMap {
+		if price, ok := lookupPrice(prices, oe.TypeID, regionID); ok {
+			oe.PricedValue = price * float64(oe.Quantity)
+			estimatedISK = estimatedISK.Add(money.FromFloat(oe.PricedValue))
+		}
 		s.TopOres = append(s.TopOres, *oe)
 	}
-	sort.Slice(s.TopOres, func(i, j int) bool {
-		return s.TopOres[i].Quantity > s.TopOres[j].Quantity
-	})
+
+	if estimatedISK.IsPositive() {
+		// Real pricing available: rank and total by ISK value, since that's
+		// what directors actually care about (a hold full of Veldspar and a
+		// handful of Mercoxit can have wildly different quantity-vs-value
+		// rankings).
+		sort.Slice(s.TopOres, func(i, j int) bool {
+			return s.TopOres[i].PricedValue > s.TopOres[j].PricedValue
+		})
+		s.EstimatedISK = money.ToFloat(estimatedISK)
+	} else {
+		sort.Slice(s.TopOres, func(i, j int) bool {
+			return s.TopOres[i].Quantity > s.TopOres[j].Quantity
+		})
+		// No pricing configured: fall back to the flat per-unit estimate.
+		s.EstimatedISK = float64(s.TotalVolume30d) * 10
+	}
 	if len(s.TopOres) > 10 {
 		s.TopOres = s.TopOres[:10]
 	}
 
-	// Rough ISK estimate: ~10 ISK per unit average (simplified)
-	s.EstimatedISK = float64(s.TotalVolume30d) * 10
-
 	return s
 }
 
@@ -485,21 +561,57 @@ func computeMiningSummary(entries []CorpMiningEntry) MiningSummary {
 // Market Summary
 // ============================================================
 
-func computeMarketSummary(orders []CorpMarketOrder) MarketSummary {
+func computeMarketSummary(orders []CorpMarketOrder, prices PriceProvider, regionID int64) MarketSummary {
 	s := MarketSummary{}
 	traderSet := make(map[int64]bool)
+	totalBuy := decimal.Zero
+	totalSell := decimal.Zero
+	totalFairValue := decimal.Zero
+	pricedAny := false
 
 	for _, o := range orders {
 		traderSet[o.CharacterID] = true
+		value := money.FromFloat(o.Price).Mul(decimal.NewFromInt(int64(o.VolumeRemain)))
 		if o.IsBuyOrder {
 			s.ActiveBuyOrders++
-			s.TotalBuyValue += o.Price * float64(o.VolumeRemain)
-		} else {
-			s.ActiveSellOrders++
-			s.TotalSellValue += o.Price * float64(o.VolumeRemain)
+			totalBuy = totalBuy.Add(value)
+			continue
+		}
+		s.ActiveSellOrders++
+		totalSell = totalSell.Add(value)
+
+		if price, ok := lookupPrice(prices, o.TypeID, regionID); ok {
+			pricedAny = true
+			o.FairValue = price * float64(o.VolumeRemain)
+			totalFairValue = totalFairValue.Add(money.FromFloat(o.FairValue))
 		}
+		s.SellOrders = append(s.SellOrders, o)
 	}
+	s.TotalBuyValue = money.ToFloat(totalBuy)
+	s.TotalSellValue = money.ToFloat(totalSell)
 	s.UniqueTraders = len(traderSet)
 
+	// UnrealizedPnL is only meaningful once at least one sell order could
+	// be priced; otherwise it'd silently read as "fully mispriced" rather
+	// than "no PriceProvider configured".
+	if pricedAny {
+		s.UnrealizedPnL = money.ToFloat(totalSell.Sub(totalFairValue))
+		sort.Slice(s.SellOrders, func(i, j int) bool {
+			return s.SellOrders[i].FairValue > s.SellOrders[j].FairValue
+		})
+	}
+	if len(s.SellOrders) > 10 {
+		s.SellOrders = s.SellOrders[:10]
+	}
+
 	return s
 }
+
+// lookupPrice is a nil-safe PriceProvider.Price call; a nil provider (no
+// pricing configured) degrades to (0, false) everywhere it's used.
+func lookupPrice(prices PriceProvider, typeID int32, regionID int64) (float64, bool) {
+	if prices == nil {
+		return 0, false
+	}
+	return prices.Price(typeID, regionID)
+}
@@ -0,0 +1,50 @@
+package corp
+
+import "testing"
+
+func TestComputeIncomeBySourceInRange_AggregatesByCategoryAndRange(t *testing.T) {
+	journal := []CorpJournalEntry{
+		{Date: "2026-07-01", Amount: 1_000_000, RefType: "bounty_prizes"},
+		{Date: "2026-07-02", Amount: 500_000, RefType: "bounty_prizes"},
+		{Date: "2026-07-03", Amount: -200_000, RefType: "brokers_fee"},
+		{Date: "2026-06-15", Amount: 9_999_999, RefType: "bounty_prizes"}, // before the window
+	}
+
+	sources := computeIncomeBySourceInRange(journal, "2026-07-01", "")
+	if len(sources) != 2 {
+		t.Fatalf("sources = %+v, want bounties + market categories only", sources)
+	}
+
+	// Sorted by |Amount| descending, so bounties (1.5M) leads market (0.2M).
+	if sources[0].Category != "bounties" || sources[0].Amount != 1_500_000 {
+		t.Errorf("sources[0] = %+v, want bounties totaling 1,500,000", sources[0])
+	}
+	if sources[1].Category != "market" || sources[1].Amount != -200_000 {
+		t.Errorf("sources[1] = %+v, want market totaling -200,000", sources[1])
+	}
+	// Percent is each category's |amount| share of total positive income
+	// (1,500,000), so even the expense-only market category gets a share.
+	if sources[0].Percent != 100 {
+		t.Errorf("sources[0].Percent = %v, want 100", sources[0].Percent)
+	}
+	if want := 13.3; sources[1].Percent != want {
+		t.Errorf("sources[1].Percent = %v, want %v (|-200,000| / 1,500,000)", sources[1].Percent, want)
+	}
+}
+
+func TestComputeIncomeBySourceInRange_UntilBoundIsExclusive(t *testing.T) {
+	journal := []CorpJournalEntry{
+		{Date: "2026-07-01", Amount: 100, RefType: "bounty_prizes"},
+		{Date: "2026-07-10", Amount: 200, RefType: "bounty_prizes"}, // on the until boundary, excluded
+	}
+	sources := computeIncomeBySourceInRange(journal, "2026-07-01", "2026-07-10")
+	if len(sources) != 1 || sources[0].Amount != 100 {
+		t.Fatalf("sources = %+v, want only the entry strictly before until", sources)
+	}
+}
+
+func TestComputeIncomeBySource_EmptyJournalReturnsNoSources(t *testing.T) {
+	if sources := computeIncomeBySource(nil, "2026-07-01"); sources != nil {
+		t.Errorf("sources = %+v, want nil for an empty journal", sources)
+	}
+}
@@ -0,0 +1,79 @@
+package corp
+
+import (
+	"sync"
+	"time"
+)
+
+// PriceProvider supplies a regional market price for an item type, used to
+// value mining output and open market orders at real market price instead
+// of a flat per-unit estimate. Implementations might hit Jita buy/sell
+// order books, Fuzzwork's price aggregator, or ESI market history. The bool
+// return is false when no price is available; callers should degrade to a
+// zero-value estimate rather than treating it as an error.
+type PriceProvider interface {
+	Price(typeID int32, regionID int64) (float64, bool)
+}
+
+// priceCacheKey identifies one PriceProvider.Price lookup.
+type priceCacheKey struct {
+	typeID   int32
+	regionID int64
+}
+
+type priceCacheEntry struct {
+	price     float64
+	ok        bool
+	expiresAt time.Time
+}
+
+// TTLPriceCache wraps a PriceProvider with a time-boxed cache keyed by
+// (typeID, regionID), so repeated dashboard rebuilds don't hammer ESI (or
+// whatever backs the provider) for prices that haven't meaningfully moved.
+// Construct one TTLPriceCache per process and reuse it across
+// BuildDashboardWithOptions calls; it is safe for concurrent use.
+type TTLPriceCache struct {
+	provider PriceProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[priceCacheKey]priceCacheEntry
+}
+
+// NewTTLPriceCache wraps provider with a cache holding each price for ttl.
+// A non-positive ttl defaults to 5 minutes.
+func NewTTLPriceCache(provider PriceProvider, ttl time.Duration) *TTLPriceCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &TTLPriceCache{
+		provider: provider,
+		ttl:      ttl,
+		entries:  make(map[priceCacheKey]priceCacheEntry),
+	}
+}
+
+// Price returns the cached price for (typeID, regionID), fetching and
+// caching it from the underlying provider on a miss or expiry. A nil
+// underlying provider degrades every lookup to (0, false).
+func (c *TTLPriceCache) Price(typeID int32, regionID int64) (float64, bool) {
+	if c.provider == nil {
+		return 0, false
+	}
+	key := priceCacheKey{typeID: typeID, regionID: regionID}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && time.Now().UTC().Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry.price, entry.ok
+	}
+	c.mu.Unlock()
+
+	price, ok := c.provider.Price(typeID, regionID)
+
+	c.mu.Lock()
+	c.entries[key] = priceCacheEntry{price: price, ok: ok, expiresAt: time.Now().UTC().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return price, ok
+}
@@ -0,0 +1,301 @@
+package corp
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"eve-flipper/internal/money"
+)
+
+// ReportPeriod is the window length (in days) a PeriodReport covers. The
+// immediately prior window of the same length is used as the comparison
+// baseline.
+type ReportPeriod int
+
+const (
+	PeriodWeekly  ReportPeriod = 7
+	PeriodMonthly ReportPeriod = 30
+)
+
+// IncomeSourceShift compares one IncomeSource category's total between the
+// current and prior window.
+type IncomeSourceShift struct {
+	Category       string  `json:"category"`
+	Label          string  `json:"label"`
+	Amount         float64 `json:"amount"`
+	PriorAmount    float64 `json:"prior_amount"`
+	AbsoluteChange float64 `json:"absolute_change"`
+	PercentChange  float64 `json:"percent_change"`
+}
+
+// PeriodReport compares the current window against the immediately prior
+// window of the same length, for a periodic digest pushed to directors via
+// a ReportSink rather than requiring them to open the dashboard.
+type PeriodReport struct {
+	Period           ReportPeriod `json:"period_days"`
+	WindowStart      string       `json:"window_start"`
+	WindowEnd        string       `json:"window_end"`
+	PriorWindowStart string       `json:"prior_window_start"`
+	PriorWindowEnd   string       `json:"prior_window_end"`
+
+	Revenue           float64 `json:"revenue"`
+	RevenueDelta      float64 `json:"revenue_delta"`
+	RevenueDeltaPct   float64 `json:"revenue_delta_pct"`
+	Expenses          float64 `json:"expenses"`
+	ExpensesDelta     float64 `json:"expenses_delta"`
+	ExpensesDeltaPct  float64 `json:"expenses_delta_pct"`
+	NetIncome         float64 `json:"net_income"`
+	NetIncomeDelta    float64 `json:"net_income_delta"`
+	NetIncomeDeltaPct float64 `json:"net_income_delta_pct"`
+
+	ActiveMembers      int `json:"active_members"`
+	ActiveMembersDelta int `json:"active_members_delta"`
+
+	IndustryJobsCompleted      int `json:"industry_jobs_completed"`
+	IndustryJobsCompletedDelta int `json:"industry_jobs_completed_delta"`
+
+	MiningVolume      int64 `json:"mining_volume"`
+	MiningVolumeDelta int64 `json:"mining_volume_delta"`
+
+	// TopMovers is the IncomeBySource categories with the largest absolute
+	// change, capped at 5, sorted by magnitude of change descending.
+	TopMovers []IncomeSourceShift `json:"top_movers"`
+
+	// NewContributors and DroppedContributors are the top-3 (by ISK) entries
+	// present in one window's TopContributors but not the other's, diffed
+	// by CharacterID.
+	NewContributors     []MemberContribution `json:"new_contributors"`
+	DroppedContributors []MemberContribution `json:"dropped_contributors"`
+}
+
+// BuildPeriodReport builds a PeriodReport for the given period, comparing
+// [now-period, now) against [now-2*period, now-period).
+func BuildPeriodReport(provider CorpDataProvider, period ReportPeriod) (*PeriodReport, error) {
+	days := int(period)
+	if days <= 0 {
+		days = int(PeriodWeekly)
+	}
+
+	// Division 1 (master wallet) is also what BuildDashboardWithOptions
+	// reads its financial overview from.
+	journal, err := provider.GetJournal(1, days*2)
+	if err != nil {
+		return nil, err
+	}
+	members, _ := provider.GetMembers()
+	industryJobs, _ := provider.GetIndustryJobs()
+	miningLedger, _ := provider.GetMiningLedger()
+
+	now := time.Now().UTC()
+	windowEnd := now.Format("2006-01-02")
+	windowStart := now.AddDate(0, 0, -days).Format("2006-01-02")
+	priorWindowEnd := windowStart
+	priorWindowStart := now.AddDate(0, 0, -days*2).Format("2006-01-02")
+
+	curRev, curExp, curActive := windowFinancials(journal, windowStart, "")
+	priorRev, priorExp, priorActive := windowFinancials(journal, priorWindowStart, priorWindowEnd)
+
+	curNet := curRev.Add(curExp)
+	priorNet := priorRev.Add(priorExp)
+
+	curJobs := countCompletedJobs(industryJobs, windowStart, "")
+	priorJobs := countCompletedJobs(industryJobs, priorWindowStart, priorWindowEnd)
+
+	curMining := sumMiningVolume(miningLedger, windowStart, "")
+	priorMining := sumMiningVolume(miningLedger, priorWindowStart, priorWindowEnd)
+
+	curSources := computeIncomeBySourceInRange(journal, windowStart, "")
+	priorSources := computeIncomeBySourceInRange(journal, priorWindowStart, priorWindowEnd)
+
+	curContributors := computeTopContributorsInRange(journal, members, windowStart, "")
+	priorContributors := computeTopContributorsInRange(journal, members, priorWindowStart, priorWindowEnd)
+
+	return &PeriodReport{
+		Period:           period,
+		WindowStart:      windowStart,
+		WindowEnd:        windowEnd,
+		PriorWindowStart: priorWindowStart,
+		PriorWindowEnd:   priorWindowEnd,
+
+		Revenue:           money.ToFloat(curRev),
+		RevenueDelta:      money.ToFloat(curRev.Sub(priorRev)),
+		RevenueDeltaPct:   deltaPct(curRev, priorRev),
+		Expenses:          money.ToFloat(curExp),
+		ExpensesDelta:     money.ToFloat(curExp.Sub(priorExp)),
+		ExpensesDeltaPct:  deltaPct(curExp, priorExp),
+		NetIncome:         money.ToFloat(curNet),
+		NetIncomeDelta:    money.ToFloat(curNet.Sub(priorNet)),
+		NetIncomeDeltaPct: deltaPct(curNet, priorNet),
+
+		ActiveMembers:      curActive,
+		ActiveMembersDelta: curActive - priorActive,
+
+		IndustryJobsCompleted:      curJobs,
+		IndustryJobsCompletedDelta: curJobs - priorJobs,
+
+		MiningVolume:      curMining,
+		MiningVolumeDelta: curMining - priorMining,
+
+		TopMovers:           topIncomeMovers(curSources, priorSources, 5),
+		NewContributors:     diffContributors(curContributors, priorContributors, 3),
+		DroppedContributors: diffContributors(priorContributors, curContributors, 3),
+	}, nil
+}
+
+// windowFinancials sums journal revenue/expenses and counts distinct
+// contributors (FirstPartyID) active within [since, until). An empty until
+// leaves the window open-ended.
+func windowFinancials(journal []CorpJournalEntry, since, until string) (revenue, expenses decimal.Decimal, activeMembers int) {
+	revenue, expenses = decimal.Zero, decimal.Zero
+	actors := make(map[int64]bool)
+	for _, e := range journal {
+		dateOnly := e.Date[:10]
+		if dateOnly < since || (until != "" && dateOnly >= until) {
+			continue
+		}
+		amount := money.FromFloat(e.Amount)
+		if amount.IsPositive() {
+			revenue = revenue.Add(amount)
+		} else {
+			expenses = expenses.Add(amount)
+		}
+		actors[e.FirstPartyID] = true
+	}
+	return revenue, expenses, len(actors)
+}
+
+// countCompletedJobs counts delivered industry jobs whose EndDate falls
+// within [since, until). An empty until leaves the window open-ended.
+func countCompletedJobs(jobs []CorpIndustryJob, since, until string) int {
+	count := 0
+	for _, j := range jobs {
+		if j.Status != "delivered" {
+			continue
+		}
+		dateOnly := j.EndDate
+		if len(dateOnly) >= 10 {
+			dateOnly = dateOnly[:10]
+		}
+		if dateOnly < since || (until != "" && dateOnly >= until) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// sumMiningVolume totals mining ledger quantity within [since, until). An
+// empty until leaves the window open-ended. Mining ledger entries carry a
+// Date field mirroring CorpJournalEntry's convention.
+func sumMiningVolume(entries []CorpMiningEntry, since, until string) int64 {
+	var total int64
+	for _, e := range entries {
+		dateOnly := e.Date
+		if len(dateOnly) >= 10 {
+			dateOnly = dateOnly[:10]
+		}
+		if dateOnly < since || (until != "" && dateOnly >= until) {
+			continue
+		}
+		total += e.Quantity
+	}
+	return total
+}
+
+// deltaPct returns the percent change of cur vs prior, rounded to 1 decimal
+// place; 0 when prior is zero (avoids a divide-by-zero on a corp's first
+// reporting period).
+func deltaPct(cur, prior decimal.Decimal) float64 {
+	if prior.IsZero() {
+		return 0
+	}
+	return money.ToFloat(money.PercentOf(cur.Sub(prior), prior.Abs()))
+}
+
+// topIncomeMovers pairs current and prior IncomeSource totals by category
+// and returns the `limit` largest absolute changes, sorted descending.
+func topIncomeMovers(current, prior []IncomeSource, limit int) []IncomeSourceShift {
+	priorByCategory := make(map[string]IncomeSource, len(prior))
+	for _, s := range prior {
+		priorByCategory[s.Category] = s
+	}
+	seen := make(map[string]bool, len(current))
+
+	shifts := make([]IncomeSourceShift, 0, len(current)+len(prior))
+	for _, cur := range current {
+		seen[cur.Category] = true
+		priorSource := priorByCategory[cur.Category]
+		shifts = append(shifts, incomeSourceShift(cur, priorSource))
+	}
+	for _, p := range prior {
+		if seen[p.Category] {
+			continue
+		}
+		shifts = append(shifts, incomeSourceShift(IncomeSource{Category: p.Category, Label: p.Label}, p))
+	}
+
+	sortShiftsByMagnitude(shifts)
+	if len(shifts) > limit {
+		shifts = shifts[:limit]
+	}
+	return shifts
+}
+
+func incomeSourceShift(cur, prior IncomeSource) IncomeSourceShift {
+	label := cur.Label
+	if label == "" {
+		label = prior.Label
+	}
+	curD := money.FromFloat(cur.Amount)
+	priorD := money.FromFloat(prior.Amount)
+	return IncomeSourceShift{
+		Category:       cur.Category,
+		Label:          label,
+		Amount:         cur.Amount,
+		PriorAmount:    prior.Amount,
+		AbsoluteChange: money.ToFloat(curD.Sub(priorD)),
+		PercentChange:  deltaPct(curD, priorD),
+	}
+}
+
+func sortShiftsByMagnitude(shifts []IncomeSourceShift) {
+	for i := 1; i < len(shifts); i++ {
+		for j := i; j > 0 && absFloat64(shifts[j].AbsoluteChange) > absFloat64(shifts[j-1].AbsoluteChange); j-- {
+			shifts[j], shifts[j-1] = shifts[j-1], shifts[j]
+		}
+	}
+}
+
+func absFloat64(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// diffContributors returns up to `limit` entries from `a` whose
+// CharacterID is absent from `b`, sorted by TotalISK descending (the
+// input slices already are, per computeTopContributors, but this re-sorts
+// defensively since the two windows may rank differently).
+func diffContributors(a, b []MemberContribution, limit int) []MemberContribution {
+	inB := make(map[int64]bool, len(b))
+	for _, m := range b {
+		inB[m.CharacterID] = true
+	}
+	var diff []MemberContribution
+	for _, m := range a {
+		if !inB[m.CharacterID] {
+			diff = append(diff, m)
+		}
+	}
+	for i := 1; i < len(diff); i++ {
+		for j := i; j > 0 && diff[j].TotalISK > diff[j-1].TotalISK; j-- {
+			diff[j], diff[j-1] = diff[j-1], diff[j]
+		}
+	}
+	if len(diff) > limit {
+		diff = diff[:limit]
+	}
+	return diff
+}
@@ -0,0 +1,55 @@
+package corp
+
+import (
+	"time"
+)
+
+// ReportSchedule configures when RunWeeklyReportScheduler fires: every
+// Weekday at HourUTC:MinuteUTC, UTC.
+type ReportSchedule struct {
+	Weekday   time.Weekday
+	HourUTC   int
+	MinuteUTC int
+}
+
+// nextRun returns the next time after `after` matching the schedule.
+func (s ReportSchedule) nextRun(after time.Time) time.Time {
+	after = after.UTC()
+	candidate := time.Date(after.Year(), after.Month(), after.Day(), s.HourUTC, s.MinuteUTC, 0, 0, time.UTC)
+	for candidate.Weekday() != s.Weekday || !candidate.After(after) {
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	return candidate
+}
+
+// RunWeeklyReportScheduler blocks, waking at each occurrence of `schedule`
+// to build a PeriodReport for `period` from `provider` and fan it out to
+// every sink. Sink errors are swallowed per-sink (so one misconfigured
+// channel doesn't block the others) and reported via onError if non-nil.
+// Call it in its own goroutine; it returns when stop is closed.
+func RunWeeklyReportScheduler(provider CorpDataProvider, period ReportPeriod, schedule ReportSchedule, sinks []ReportSink, onError func(sink string, err error), stop <-chan struct{}) {
+	for {
+		now := time.Now().UTC()
+		next := schedule.nextRun(now)
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			report, err := BuildPeriodReport(provider, period)
+			if err != nil {
+				if onError != nil {
+					onError("build", err)
+				}
+				continue
+			}
+			for _, sink := range sinks {
+				if err := sink.Send(report); err != nil && onError != nil {
+					onError(sink.Name(), err)
+				}
+			}
+		}
+	}
+}
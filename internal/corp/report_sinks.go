@@ -0,0 +1,129 @@
+package corp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+var reportHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// ReportSink delivers a PeriodReport to a destination, modeled on
+// alerts.Sink's Name()/Send() shape.
+type ReportSink interface {
+	Name() string
+	Send(*PeriodReport) error
+}
+
+// DiscordReportSink posts a compact PeriodReport summary to a Discord
+// webhook, reusing the same webhook-post shape as alerts.DiscordSink.
+type DiscordReportSink struct {
+	WebhookURL string
+}
+
+func (s *DiscordReportSink) Name() string { return "discord" }
+
+func (s *DiscordReportSink) Send(r *PeriodReport) error {
+	if s.WebhookURL == "" {
+		return fmt.Errorf("discord report sink not configured")
+	}
+	payload, _ := json.Marshal(map[string]string{"content": FormatReportText(r)})
+	resp, err := reportHTTPClient.Post(s.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailReportSink sends a PeriodReport over SMTP to a fixed recipient list.
+type EmailReportSink struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (s *EmailReportSink) Name() string { return "email" }
+
+func (s *EmailReportSink) Send(r *PeriodReport) error {
+	if s.Host == "" || s.Port == "" || len(s.To) == 0 {
+		return fmt.Errorf("email report sink not configured")
+	}
+	subject := fmt.Sprintf("Corp digest: %s to %s", r.WindowStart, r.WindowEnd)
+	body := FormatReportText(r)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		s.From, strings.Join(s.To, ", "), subject, body)
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := s.Host + ":" + s.Port
+	return smtp.SendMail(addr, auth, s.From, s.To, []byte(msg))
+}
+
+// JSONFileReportSink writes a PeriodReport as a JSON file, overwriting any
+// existing file at Path. Useful for archiving every digest alongside a
+// scheduled run, or for local testing without a webhook/SMTP server.
+type JSONFileReportSink struct {
+	Path string
+}
+
+func (s *JSONFileReportSink) Name() string { return "json_file" }
+
+func (s *JSONFileReportSink) Send(r *PeriodReport) error {
+	if s.Path == "" {
+		return fmt.Errorf("json file report sink not configured")
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0o644)
+}
+
+// FormatReportText renders a PeriodReport as a compact plain-text summary:
+// headline financials, the top income-category movers, and new vs dropped
+// contributors. Shared by DiscordReportSink and EmailReportSink so both
+// channels read the same digest.
+func FormatReportText(r *PeriodReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Corp digest: %s to %s (prior: %s to %s)\n", r.WindowStart, r.WindowEnd, r.PriorWindowStart, r.PriorWindowEnd)
+	fmt.Fprintf(&b, "Revenue: %.2f ISK (%+.1f%%)\n", r.Revenue, r.RevenueDeltaPct)
+	fmt.Fprintf(&b, "Expenses: %.2f ISK (%+.1f%%)\n", r.Expenses, r.ExpensesDeltaPct)
+	fmt.Fprintf(&b, "Net income: %.2f ISK (%+.1f%%)\n", r.NetIncome, r.NetIncomeDeltaPct)
+	fmt.Fprintf(&b, "Active members: %d (%+d)\n", r.ActiveMembers, r.ActiveMembersDelta)
+	fmt.Fprintf(&b, "Industry jobs completed: %d (%+d)\n", r.IndustryJobsCompleted, r.IndustryJobsCompletedDelta)
+	fmt.Fprintf(&b, "Mining volume: %d (%+d)\n", r.MiningVolume, r.MiningVolumeDelta)
+
+	if len(r.TopMovers) > 0 {
+		b.WriteString("\nTop movers:\n")
+		for _, m := range r.TopMovers {
+			fmt.Fprintf(&b, "  %s: %.2f ISK (%+.2f, %+.1f%%)\n", m.Label, m.Amount, m.AbsoluteChange, m.PercentChange)
+		}
+	}
+	if len(r.NewContributors) > 0 {
+		b.WriteString("\nNew contributors:\n")
+		for _, m := range r.NewContributors {
+			fmt.Fprintf(&b, "  %s (%s): %.2f ISK\n", m.Name, m.Category, m.TotalISK)
+		}
+	}
+	if len(r.DroppedContributors) > 0 {
+		b.WriteString("\nDropped contributors:\n")
+		for _, m := range r.DroppedContributors {
+			fmt.Fprintf(&b, "  %s (%s): %.2f ISK\n", m.Name, m.Category, m.TotalISK)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+)
+
+const createAlertActiveTableSQL = `
+CREATE TABLE IF NOT EXISTS alert_active (
+	fingerprint       TEXT PRIMARY KEY,
+	watchlist_type_id INTEGER NOT NULL,
+	alert_metric      TEXT NOT NULL,
+	threshold         REAL NOT NULL,
+	message           TEXT NOT NULL,
+	raised_at         TEXT NOT NULL,
+	updated_at        TEXT NOT NULL
+);`
+
+// ActiveWatchlistAlert is the currently-open incident for one (watchlist
+// item, metric) pair, keyed by its stable Fingerprint so repeat firings
+// update the same row instead of opening a new one each scan.
+type ActiveWatchlistAlert struct {
+	Fingerprint     string  `json:"fingerprint"`
+	WatchlistTypeID int32   `json:"watchlist_type_id"`
+	Metric          string  `json:"alert_metric"`
+	Threshold       float64 `json:"threshold"`
+	Message         string  `json:"message"`
+	RaisedAt        string  `json:"raised_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+// ComputeAlertFingerprint derives a stable incident ID for a (watchlist
+// item, metric, threshold) alert, so outbound channels can edit the prior
+// message instead of spamming a new one on every repeat firing. Threshold is
+// bucketed to 4 decimal places first so float jitter between scans doesn't
+// mint a new fingerprint for what's really the same configured alert.
+func ComputeAlertFingerprint(typeID int32, metric string, threshold float64) string {
+	bucketed := math.Round(threshold*10000) / 10000
+	raw := fmt.Sprintf("%d|%s|%.4f", typeID, metric, bucketed)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func (d *DB) ensureAlertActiveTable() error {
+	_, err := d.sql.Exec(createAlertActiveTableSQL)
+	return err
+}
+
+// UpsertActiveAlert records or refreshes the open incident for alert.
+// Fingerprint, inserting raised_at only on first fire and otherwise just
+// bumping message/threshold/updated_at.
+func (d *DB) UpsertActiveAlert(alert ActiveWatchlistAlert) error {
+	if err := d.ensureAlertActiveTable(); err != nil {
+		return err
+	}
+	if alert.RaisedAt == "" {
+		alert.RaisedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	if alert.UpdatedAt == "" {
+		alert.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	_, err := d.sql.Exec(`
+		INSERT INTO alert_active (fingerprint, watchlist_type_id, alert_metric, threshold, message, raised_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(fingerprint) DO UPDATE SET
+			message   = excluded.message,
+			threshold = excluded.threshold,
+			updated_at = excluded.updated_at
+	`, alert.Fingerprint, alert.WatchlistTypeID, alert.Metric, alert.Threshold, alert.Message, alert.RaisedAt, alert.UpdatedAt)
+	return err
+}
+
+// GetActiveAlerts returns every currently-open watchlist incident, for
+// rendering a status badge or feeding Resolved-event comparisons.
+func (d *DB) GetActiveAlerts() ([]ActiveWatchlistAlert, error) {
+	if err := d.ensureAlertActiveTable(); err != nil {
+		return nil, err
+	}
+	rows, err := d.sql.Query(`
+		SELECT fingerprint, watchlist_type_id, alert_metric, threshold, message, raised_at, updated_at
+		  FROM alert_active
+		 ORDER BY raised_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ActiveWatchlistAlert
+	for rows.Next() {
+		var a ActiveWatchlistAlert
+		if err := rows.Scan(&a.Fingerprint, &a.WatchlistTypeID, &a.Metric, &a.Threshold, &a.Message, &a.RaisedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	if out == nil {
+		out = []ActiveWatchlistAlert{}
+	}
+	return out, nil
+}
+
+// ResolveAlert closes the open incident for fingerprint: it removes the
+// alert_active row and stamps AlertResolvedAt on every still-unresolved
+// alert_history entry for fingerprint, so the performance report can later account for how
+// long the incident stayed open. resolved is false if there was no open
+// incident for fingerprint to close.
+func (d *DB) ResolveAlert(fingerprint string) (resolved bool, err error) {
+	if err := d.ensureAlertActiveTable(); err != nil {
+		return false, err
+	}
+	res, err := d.sql.Exec(`DELETE FROM alert_active WHERE fingerprint = ?`, fingerprint)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, nil
+	}
+	_, err = d.sql.Exec(`
+		UPDATE alert_history SET alert_resolved_at = ?
+		 WHERE fingerprint = ? AND alert_resolved_at IS NULL
+	`, time.Now().UTC().Format(time.RFC3339), fingerprint)
+	return true, err
+}
@@ -0,0 +1,93 @@
+package db
+
+import "testing"
+
+func TestComputeAlertFingerprint_StableAndBucketed(t *testing.T) {
+	a := ComputeAlertFingerprint(34, "margin_percent", 10.00001)
+	b := ComputeAlertFingerprint(34, "margin_percent", 10.00002)
+	if a != b {
+		t.Errorf("expected bucketed thresholds to share a fingerprint: %s != %s", a, b)
+	}
+
+	c := ComputeAlertFingerprint(34, "margin_percent", 12.0)
+	if a == c {
+		t.Error("expected a materially different threshold to produce a different fingerprint")
+	}
+
+	d := ComputeAlertFingerprint(35, "margin_percent", 10.0)
+	if a == d {
+		t.Error("expected a different type ID to produce a different fingerprint")
+	}
+}
+
+func TestDB_ActiveAlert_UpsertGetResolve(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	fp := ComputeAlertFingerprint(34, "margin_percent", 10.0)
+	if err := d.UpsertActiveAlert(ActiveWatchlistAlert{
+		Fingerprint:     fp,
+		WatchlistTypeID: 34,
+		Metric:          "margin_percent",
+		Threshold:       10.0,
+		Message:         "Tritanium: Margin 15.00% >= 10.00%",
+	}); err != nil {
+		t.Fatalf("UpsertActiveAlert failed: %v", err)
+	}
+
+	active, err := d.GetActiveAlerts()
+	if err != nil {
+		t.Fatalf("GetActiveAlerts failed: %v", err)
+	}
+	if len(active) != 1 || active[0].Fingerprint != fp {
+		t.Fatalf("unexpected active alerts: %+v", active)
+	}
+	firstRaisedAt := active[0].RaisedAt
+
+	// Re-firing the same incident should update in place, not duplicate,
+	// and should preserve the original raised_at.
+	if err := d.UpsertActiveAlert(ActiveWatchlistAlert{
+		Fingerprint:     fp,
+		WatchlistTypeID: 34,
+		Metric:          "margin_percent",
+		Threshold:       10.0,
+		Message:         "Tritanium: Margin 18.00% >= 10.00%",
+	}); err != nil {
+		t.Fatalf("UpsertActiveAlert (update) failed: %v", err)
+	}
+	active, err = d.GetActiveAlerts()
+	if err != nil {
+		t.Fatalf("GetActiveAlerts failed: %v", err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("expected re-firing to update in place, got %d rows", len(active))
+	}
+	if active[0].Message != "Tritanium: Margin 18.00% >= 10.00%" {
+		t.Errorf("expected message to update, got %q", active[0].Message)
+	}
+	if active[0].RaisedAt != firstRaisedAt {
+		t.Errorf("expected raised_at to stay %q, got %q", firstRaisedAt, active[0].RaisedAt)
+	}
+
+	resolved, err := d.ResolveAlert(fp)
+	if err != nil {
+		t.Fatalf("ResolveAlert failed: %v", err)
+	}
+	if !resolved {
+		t.Error("expected ResolveAlert to report an open incident was closed")
+	}
+	resolvedAgain, err := d.ResolveAlert(fp)
+	if err != nil {
+		t.Fatalf("ResolveAlert (second call) failed: %v", err)
+	}
+	if resolvedAgain {
+		t.Error("expected ResolveAlert to report nothing to close on a second call")
+	}
+	active, err = d.GetActiveAlerts()
+	if err != nil {
+		t.Fatalf("GetActiveAlerts failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("expected no active alerts after resolve, got %d", len(active))
+	}
+}
@@ -19,6 +19,13 @@ type AlertHistoryEntry struct {
 	ChannelsFailed  map[string]string `json:"channels_failed,omitempty"`
 	SentAt          string            `json:"sent_at"`
 	ScanID          *int64            `json:"scan_id,omitempty"`
+
+	// Fingerprint is the stable incident ID from ComputeAlertFingerprint,
+	// shared with the alert_active row this entry opened or closed.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// AlertResolvedAt is set by ResolveAlert once the metric falls back
+	// under threshold, closing out the incident this entry opened.
+	AlertResolvedAt string `json:"alert_resolved_at,omitempty"`
 }
 
 // SaveAlertHistory records a sent alert to the history table.
@@ -36,8 +43,9 @@ func (d *DB) SaveAlertHistory(entry AlertHistoryEntry) error {
 	_, err := d.sql.Exec(`
 		INSERT INTO alert_history (
 			watchlist_type_id, type_name, alert_metric, alert_threshold,
-			current_value, message, channels_sent, channels_failed, sent_at, scan_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			current_value, message, channels_sent, channels_failed, sent_at, scan_id,
+			fingerprint
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		entry.WatchlistTypeID,
 		entry.TypeName,
 		entry.AlertMetric,
@@ -48,6 +56,7 @@ func (d *DB) SaveAlertHistory(entry AlertHistoryEntry) error {
 		string(channelsFailedJSON),
 		entry.SentAt,
 		entry.ScanID,
+		entry.Fingerprint,
 	)
 	return err
 }
@@ -57,7 +66,8 @@ func (d *DB) SaveAlertHistory(entry AlertHistoryEntry) error {
 func (d *DB) GetAlertHistory(typeID int32, limit int) ([]AlertHistoryEntry, error) {
 	query := `
 		SELECT id, watchlist_type_id, type_name, alert_metric, alert_threshold,
-		       current_value, message, channels_sent, channels_failed, sent_at, scan_id
+		       current_value, message, channels_sent, channels_failed, sent_at, scan_id,
+		       fingerprint, alert_resolved_at
 		  FROM alert_history
 	`
 	args := []interface{}{}
@@ -82,6 +92,7 @@ func (d *DB) GetAlertHistory(typeID int32, limit int) ([]AlertHistoryEntry, erro
 		var e AlertHistoryEntry
 		var channelsSentStr, channelsFailedStr sql.NullString
 		var scanID sql.NullInt64
+		var fingerprint, alertResolvedAt sql.NullString
 
 		if err := rows.Scan(
 			&e.ID,
@@ -95,6 +106,8 @@ func (d *DB) GetAlertHistory(typeID int32, limit int) ([]AlertHistoryEntry, erro
 			&channelsFailedStr,
 			&e.SentAt,
 			&scanID,
+			&fingerprint,
+			&alertResolvedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -109,6 +122,8 @@ func (d *DB) GetAlertHistory(typeID int32, limit int) ([]AlertHistoryEntry, erro
 			sid := scanID.Int64
 			e.ScanID = &sid
 		}
+		e.Fingerprint = fingerprint.String
+		e.AlertResolvedAt = alertResolvedAt.String
 
 		entries = append(entries, e)
 	}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// AlertOutcome pairs a historical alert with what its underlying price
+// actually did over the following lookahead window, using cached ESI market
+// history as the source of truth — alert_history itself only records the
+// metric's own value (margin%, pivot breakout%, etc.), not the raw price a
+// user would have filled at.
+type AlertOutcome struct {
+	AlertHistoryEntry
+	EntryPrice     float64 // average price of the first history day on or after the alert's sent_at
+	ExitPrice      float64 // average price of the first history day on or after sent_at + lookaheadHours
+	RealizedReturn float64 // % change from EntryPrice to ExitPrice
+	Hit            bool    // true if RealizedReturn >= 0: price moved favorably after the alert
+}
+
+// AlertStats summarizes retrospective alert performance for threshold
+// tuning, treating each alert as a hypothetical trade entered at the alert
+// price and exited at the end of the lookahead window.
+type AlertStats struct {
+	HitRate                float64   // fraction of outcomes with Hit == true
+	MeanRealizedReturn     float64   // mean RealizedReturn (%) across outcomes
+	Sharpe                 float64   // mean/stddev of per-alert returns, annualized by sqrt(365)
+	MaxDrawdown            float64   // max peak-to-trough drop (%) in the running equity curve
+	Profit                 float64   // compounded total return (%) of the equity curve
+	MaxFalsePositiveStreak int       // longest run of consecutive non-hits
+	EquityCurve            []float64 // running compounded equity, starting at 1.0, one point per outcome in chronological order
+}
+
+// GetAlertHistoryWithOutcomes joins the alert_history rows for typeID (or
+// every type if typeID is 0) against history, the caller-preloaded market
+// history for each type (mirroring backtest.HistoryByType — db has no live
+// ESI access of its own), and reports what the price actually did over the
+// following lookaheadHours. Alerts with no history entry on or after their
+// sent_at, or none at least lookaheadHours later, are skipped since their
+// outcome can't be determined yet.
+func (d *DB) GetAlertHistoryWithOutcomes(typeID int32, lookaheadHours int, history map[int32][]esi.HistoryEntry) ([]AlertOutcome, error) {
+	entries, err := d.GetAlertHistory(typeID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var outcomes []AlertOutcome
+	for _, e := range entries {
+		sorted, ok := sortedHistory(history, e.WatchlistTypeID)
+		if !ok {
+			continue
+		}
+
+		sentAt, err := time.Parse(time.RFC3339, e.SentAt)
+		if err != nil {
+			continue
+		}
+
+		entryPrice, ok := priceOnOrAfter(sorted, sentAt)
+		if !ok || entryPrice <= 0 {
+			continue
+		}
+		exitPrice, ok := priceOnOrAfter(sorted, sentAt.Add(time.Duration(lookaheadHours)*time.Hour))
+		if !ok {
+			continue
+		}
+
+		realizedReturn := (exitPrice - entryPrice) / entryPrice * 100
+		outcomes = append(outcomes, AlertOutcome{
+			AlertHistoryEntry: e,
+			EntryPrice:        entryPrice,
+			ExitPrice:         exitPrice,
+			RealizedReturn:    realizedReturn,
+			Hit:               realizedReturn >= 0,
+		})
+	}
+	return outcomes, nil
+}
+
+// sortedHistory returns typeID's history sorted ascending by date, since ESI
+// history is not guaranteed to arrive in chronological order.
+func sortedHistory(history map[int32][]esi.HistoryEntry, typeID int32) ([]esi.HistoryEntry, bool) {
+	entries, ok := history[typeID]
+	if !ok || len(entries) == 0 {
+		return nil, false
+	}
+	sorted := make([]esi.HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	return sorted, true
+}
+
+// priceOnOrAfter returns the Average price of the first entry (assumed
+// sorted ascending by date) whose date is on or after t.
+func priceOnOrAfter(sorted []esi.HistoryEntry, t time.Time) (float64, bool) {
+	cutoff := t.Format("2006-01-02")
+	for _, entry := range sorted {
+		if entry.Date >= cutoff {
+			return entry.Average, true
+		}
+	}
+	return 0, false
+}
+
+// ComputeAlertStats computes aggregate performance statistics over a set of
+// alert outcomes (see GetAlertHistoryWithOutcomes).
+func ComputeAlertStats(outcomes []AlertOutcome) AlertStats {
+	if len(outcomes) == 0 {
+		return AlertStats{}
+	}
+
+	sorted := make([]AlertOutcome, len(outcomes))
+	copy(sorted, outcomes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].SentAt < sorted[j].SentAt })
+
+	n := float64(len(sorted))
+	var hits int
+	var sumReturn float64
+	for _, o := range sorted {
+		sumReturn += o.RealizedReturn
+		if o.Hit {
+			hits++
+		}
+	}
+	meanReturn := sumReturn / n
+
+	var sumSqDiff float64
+	for _, o := range sorted {
+		diff := o.RealizedReturn - meanReturn
+		sumSqDiff += diff * diff
+	}
+	stddev := math.Sqrt(sumSqDiff / n)
+
+	sharpe := 0.0
+	if stddev > 0 {
+		sharpe = meanReturn / stddev * math.Sqrt(365)
+	}
+
+	equity := 1.0
+	peak := 1.0
+	maxDrawdown := 0.0
+	equityCurve := make([]float64, 0, len(sorted))
+	for _, o := range sorted {
+		equity *= 1 + o.RealizedReturn/100
+		equityCurve = append(equityCurve, equity)
+		if equity > peak {
+			peak = equity
+		}
+		if drawdown := (peak - equity) / peak * 100; drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+	}
+
+	streak, maxStreak := 0, 0
+	for _, o := range sorted {
+		if o.Hit {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > maxStreak {
+			maxStreak = streak
+		}
+	}
+
+	return AlertStats{
+		HitRate:                float64(hits) / n,
+		MeanRealizedReturn:     meanReturn,
+		Sharpe:                 sharpe,
+		MaxDrawdown:            maxDrawdown,
+		Profit:                 (equity - 1) * 100,
+		MaxFalsePositiveStreak: maxStreak,
+		EquityCurve:            equityCurve,
+	}
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/esi"
+)
+
+func TestGetAlertHistoryWithOutcomes_JoinsHistoryAndComputesReturn(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	d.AddWatchlistItem(config.WatchlistItem{
+		TypeID:         34,
+		TypeName:       "Tritanium",
+		AddedAt:        time.Now().UTC().Format(time.RFC3339),
+		AlertEnabled:   true,
+		AlertMetric:    "margin_percent",
+		AlertThreshold: 10.0,
+	})
+
+	sentAt := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	if err := d.SaveAlertHistory(AlertHistoryEntry{
+		WatchlistTypeID: 34,
+		TypeName:        "Tritanium",
+		AlertMetric:     "margin_percent",
+		AlertThreshold:  10.0,
+		CurrentValue:    15.0,
+		Message:         "test",
+		ChannelsSent:    []string{"desktop"},
+		SentAt:          sentAt.Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveAlertHistory failed: %v", err)
+	}
+
+	history := map[int32][]esi.HistoryEntry{
+		34: {
+			{Date: "2026-01-04", Average: 100},
+			{Date: "2026-01-05", Average: 100},
+			{Date: "2026-01-06", Average: 105},
+			{Date: "2026-01-07", Average: 110},
+		},
+	}
+
+	outcomes, err := d.GetAlertHistoryWithOutcomes(34, 48, history)
+	if err != nil {
+		t.Fatalf("GetAlertHistoryWithOutcomes failed: %v", err)
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, got %d", len(outcomes))
+	}
+
+	o := outcomes[0]
+	if o.EntryPrice != 100 {
+		t.Errorf("expected EntryPrice=100, got %f", o.EntryPrice)
+	}
+	if o.ExitPrice != 110 {
+		t.Errorf("expected ExitPrice=110, got %f", o.ExitPrice)
+	}
+	if o.RealizedReturn != 10 {
+		t.Errorf("expected RealizedReturn=10, got %f", o.RealizedReturn)
+	}
+	if !o.Hit {
+		t.Error("expected Hit=true for a positive realized return")
+	}
+}
+
+func TestGetAlertHistoryWithOutcomes_SkipsAlertsWithoutEnoughLaterHistory(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	d.AddWatchlistItem(config.WatchlistItem{
+		TypeID:         34,
+		TypeName:       "Tritanium",
+		AddedAt:        time.Now().UTC().Format(time.RFC3339),
+		AlertEnabled:   true,
+		AlertMetric:    "margin_percent",
+		AlertThreshold: 10.0,
+	})
+
+	sentAt := time.Now().UTC()
+	if err := d.SaveAlertHistory(AlertHistoryEntry{
+		WatchlistTypeID: 34,
+		TypeName:        "Tritanium",
+		AlertMetric:     "margin_percent",
+		AlertThreshold:  10.0,
+		CurrentValue:    15.0,
+		Message:         "test",
+		ChannelsSent:    []string{"desktop"},
+		SentAt:          sentAt.Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveAlertHistory failed: %v", err)
+	}
+
+	// No history at all for the type.
+	outcomes, err := d.GetAlertHistoryWithOutcomes(34, 48, map[int32][]esi.HistoryEntry{})
+	if err != nil {
+		t.Fatalf("GetAlertHistoryWithOutcomes failed: %v", err)
+	}
+	if len(outcomes) != 0 {
+		t.Errorf("expected 0 outcomes with no history, got %d", len(outcomes))
+	}
+}
+
+func TestComputeAlertStats(t *testing.T) {
+	outcomes := []AlertOutcome{
+		{AlertHistoryEntry: AlertHistoryEntry{SentAt: "2026-01-01T00:00:00Z"}, RealizedReturn: 10, Hit: true},
+		{AlertHistoryEntry: AlertHistoryEntry{SentAt: "2026-01-02T00:00:00Z"}, RealizedReturn: -5, Hit: false},
+		{AlertHistoryEntry: AlertHistoryEntry{SentAt: "2026-01-03T00:00:00Z"}, RealizedReturn: -5, Hit: false},
+		{AlertHistoryEntry: AlertHistoryEntry{SentAt: "2026-01-04T00:00:00Z"}, RealizedReturn: 20, Hit: true},
+	}
+
+	stats := ComputeAlertStats(outcomes)
+
+	if stats.HitRate != 0.5 {
+		t.Errorf("expected HitRate=0.5, got %f", stats.HitRate)
+	}
+	if stats.MeanRealizedReturn != 5 {
+		t.Errorf("expected MeanRealizedReturn=5, got %f", stats.MeanRealizedReturn)
+	}
+	if stats.MaxFalsePositiveStreak != 2 {
+		t.Errorf("expected MaxFalsePositiveStreak=2, got %d", stats.MaxFalsePositiveStreak)
+	}
+	if len(stats.EquityCurve) != 4 {
+		t.Fatalf("expected 4 equity curve points, got %d", len(stats.EquityCurve))
+	}
+	if stats.MaxDrawdown <= 0 {
+		t.Errorf("expected a positive MaxDrawdown after two consecutive losses, got %f", stats.MaxDrawdown)
+	}
+	if stats.Profit <= 0 {
+		t.Errorf("expected positive overall Profit, got %f", stats.Profit)
+	}
+}
+
+func TestComputeAlertStats_Empty(t *testing.T) {
+	stats := ComputeAlertStats(nil)
+	if stats.HitRate != 0 || stats.MeanRealizedReturn != 0 || len(stats.EquityCurve) != 0 {
+		t.Errorf("expected zero-value stats for no outcomes, got %+v", stats)
+	}
+}
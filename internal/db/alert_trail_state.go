@@ -0,0 +1,75 @@
+package db
+
+const createAlertTrailStateTableSQL = `
+CREATE TABLE IF NOT EXISTS alert_trail_state (
+	watchlist_type_id INTEGER NOT NULL,
+	metric            TEXT NOT NULL,
+	peak              REAL NOT NULL,
+	active_tier       INTEGER NOT NULL,
+	updated_at        TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+	PRIMARY KEY (watchlist_type_id, metric)
+);`
+
+// AlertTrailState is the persisted trailing-alert state for one watchlist
+// item/metric pair: the highest value seen since the metric crossed its
+// base threshold, and which
+// activation tier (index into the item's TrailingActivationRatios) is
+// currently armed. ActiveTier follows engine.TrackedPosition's convention
+// of -1 meaning no tier armed yet.
+type AlertTrailState struct {
+	WatchlistTypeID int32
+	Metric          string
+	Peak            float64
+	ActiveTier      int
+}
+
+func (d *DB) ensureAlertTrailStateTable() error {
+	_, err := d.sql.Exec(createAlertTrailStateTableSQL)
+	return err
+}
+
+// GetAlertTrailState returns the persisted trailing state for (typeID,
+// metric), or ok=false if none has been saved since the last reset.
+func (d *DB) GetAlertTrailState(typeID int32, metric string) (state AlertTrailState, ok bool) {
+	if err := d.ensureAlertTrailStateTable(); err != nil {
+		return AlertTrailState{}, false
+	}
+	err := d.sql.QueryRow(`
+		SELECT watchlist_type_id, metric, peak, active_tier
+		  FROM alert_trail_state WHERE watchlist_type_id = ? AND metric = ?
+	`, typeID, metric).Scan(&state.WatchlistTypeID, &state.Metric, &state.Peak, &state.ActiveTier)
+	if err != nil {
+		return AlertTrailState{}, false
+	}
+	return state, true
+}
+
+// SaveAlertTrailState upserts the trailing state for (typeID, metric),
+// called on every scan while the metric sits above its base threshold
+// (armed or not) so a restart resumes from the same peak and tier instead
+// of re-arming from scratch.
+func (d *DB) SaveAlertTrailState(state AlertTrailState) error {
+	if err := d.ensureAlertTrailStateTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		INSERT INTO alert_trail_state (watchlist_type_id, metric, peak, active_tier, updated_at)
+		VALUES (?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(watchlist_type_id, metric) DO UPDATE SET
+			peak = excluded.peak,
+			active_tier = excluded.active_tier,
+			updated_at = excluded.updated_at
+	`, state.WatchlistTypeID, state.Metric, state.Peak, state.ActiveTier)
+	return err
+}
+
+// ResetAlertTrailState clears trailing state once the metric falls back
+// below the item's base threshold, so the next breach re-arms from tier -1
+// rather than resuming a stale peak.
+func (d *DB) ResetAlertTrailState(typeID int32, metric string) error {
+	if err := d.ensureAlertTrailStateTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`DELETE FROM alert_trail_state WHERE watchlist_type_id = ? AND metric = ?`, typeID, metric)
+	return err
+}
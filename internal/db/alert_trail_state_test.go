@@ -0,0 +1,44 @@
+package db
+
+import "testing"
+
+func TestDB_AlertTrailState_SaveGetReset(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	if _, ok := d.GetAlertTrailState(34, "margin_percent"); ok {
+		t.Fatal("expected no trail state before Save")
+	}
+
+	state := AlertTrailState{WatchlistTypeID: 34, Metric: "margin_percent", Peak: 42.0, ActiveTier: 1}
+	if err := d.SaveAlertTrailState(state); err != nil {
+		t.Fatalf("SaveAlertTrailState failed: %v", err)
+	}
+
+	got, ok := d.GetAlertTrailState(34, "margin_percent")
+	if !ok {
+		t.Fatal("expected trail state after Save")
+	}
+	if got.Peak != 42.0 || got.ActiveTier != 1 {
+		t.Errorf("unexpected state: %+v", got)
+	}
+
+	// Saving again for the same (typeID, metric) should update in place,
+	// not create a second row.
+	state.Peak = 45.0
+	state.ActiveTier = 2
+	if err := d.SaveAlertTrailState(state); err != nil {
+		t.Fatalf("SaveAlertTrailState (update) failed: %v", err)
+	}
+	got, ok = d.GetAlertTrailState(34, "margin_percent")
+	if !ok || got.Peak != 45.0 || got.ActiveTier != 2 {
+		t.Fatalf("unexpected state after update: %+v, ok=%v", got, ok)
+	}
+
+	if err := d.ResetAlertTrailState(34, "margin_percent"); err != nil {
+		t.Fatalf("ResetAlertTrailState failed: %v", err)
+	}
+	if _, ok := d.GetAlertTrailState(34, "margin_percent"); ok {
+		t.Fatal("expected no trail state after Reset")
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import "time"
+
+const createAlertsTableSQL = `
+CREATE TABLE IF NOT EXISTS alerts (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	alert_type  TEXT NOT NULL,
+	alert_key   TEXT NOT NULL,
+	severity    TEXT NOT NULL,
+	message     TEXT NOT NULL,
+	raised_at   TEXT NOT NULL,
+	resolved_at TEXT,
+	UNIQUE(alert_type, alert_key, raised_at)
+);`
+
+// ActiveAlert is a currently-open (unresolved) alert keyed by a generic
+// (alertType, alertKey) pair. internal/alerts.Alerter tracks its own active
+// incidents in memory and doesn't persist here, and the watchlist scan flow
+// persists through the fingerprint-keyed alert_active table instead (see
+// ActiveWatchlistAlert); this type is a standalone primitive for any future
+// caller that wants a durable, restart-surviving active-alert ledger.
+type ActiveAlert struct {
+	ID        int64  `json:"id"`
+	AlertType string `json:"alert_type"`
+	AlertKey  string `json:"alert_key"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	RaisedAt  string `json:"raised_at"`
+}
+
+func (d *DB) ensureAlertsTable() error {
+	_, err := d.sql.Exec(createAlertsTableSQL)
+	return err
+}
+
+// SaveActiveAlert records a newly-raised alert.
+func (d *DB) SaveActiveAlert(alertType, alertKey, severity, message string) error {
+	if err := d.ensureAlertsTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		INSERT INTO alerts (alert_type, alert_key, severity, message, raised_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		alertType, alertKey, severity, message, time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// ResolveActiveAlert marks the most recent open alert for (alertType,
+// alertKey) as resolved.
+func (d *DB) ResolveActiveAlert(alertType, alertKey string) error {
+	if err := d.ensureAlertsTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		UPDATE alerts SET resolved_at = ?
+		 WHERE alert_type = ? AND alert_key = ? AND resolved_at IS NULL`,
+		time.Now().UTC().Format(time.RFC3339), alertType, alertKey,
+	)
+	return err
+}
+
+// GetOpenAlerts returns all currently-unresolved alerts for the UI status
+// badge.
+func (d *DB) GetOpenAlerts() ([]ActiveAlert, error) {
+	if err := d.ensureAlertsTable(); err != nil {
+		return nil, err
+	}
+	rows, err := d.sql.Query(`
+		SELECT id, alert_type, alert_key, severity, message, raised_at
+		  FROM alerts WHERE resolved_at IS NULL
+		 ORDER BY raised_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ActiveAlert
+	for rows.Next() {
+		var a ActiveAlert
+		if err := rows.Scan(&a.ID, &a.AlertType, &a.AlertKey, &a.Severity, &a.Message, &a.RaisedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	if out == nil {
+		out = []ActiveAlert{}
+	}
+	return out, nil
+}
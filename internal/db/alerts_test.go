@@ -0,0 +1,58 @@
+package db
+
+import "testing"
+
+func TestDB_ActiveAlerts_RaiseAndResolve(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	if err := d.SaveActiveAlert("margin_breach", "34", "warning", "Tritanium margin 15% >= 10%"); err != nil {
+		t.Fatalf("SaveActiveAlert failed: %v", err)
+	}
+
+	open, err := d.GetOpenAlerts()
+	if err != nil {
+		t.Fatalf("GetOpenAlerts failed: %v", err)
+	}
+	if len(open) != 1 {
+		t.Fatalf("GetOpenAlerts len = %d, want 1", len(open))
+	}
+	if open[0].AlertType != "margin_breach" || open[0].AlertKey != "34" {
+		t.Errorf("unexpected alert: %+v", open[0])
+	}
+
+	if err := d.ResolveActiveAlert("margin_breach", "34"); err != nil {
+		t.Fatalf("ResolveActiveAlert failed: %v", err)
+	}
+
+	open, err = d.GetOpenAlerts()
+	if err != nil {
+		t.Fatalf("GetOpenAlerts after resolve failed: %v", err)
+	}
+	if len(open) != 0 {
+		t.Fatalf("GetOpenAlerts after resolve len = %d, want 0", len(open))
+	}
+}
+
+func TestDB_Migrate_AlertsTableExists(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	if err := d.ensureAlertsTable(); err != nil {
+		t.Fatalf("ensureAlertsTable: %v", err)
+	}
+	rows, err := d.sql.Query("PRAGMA table_info(alerts)")
+	if err != nil {
+		t.Fatalf("PRAGMA table_info(alerts): %v", err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		found = true
+		break
+	}
+	if !found {
+		t.Error("alerts table has no columns, want schema to exist")
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"encoding/json"
+	"log"
+
+	"eve-flipper/internal/backtest"
+)
+
+const createBacktestRunsTableSQL = `
+CREATE TABLE IF NOT EXISTS backtest_runs (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	start_date       TEXT NOT NULL,
+	end_date         TEXT NOT NULL,
+	starting_balance REAL NOT NULL,
+	final_balance    REAL NOT NULL,
+	sharpe_ratio     REAL NOT NULL,
+	sortino_ratio    REAL NOT NULL,
+	profit_factor    REAL NOT NULL,
+	winning_ratio    REAL NOT NULL,
+	max_drawdown     REAL NOT NULL,
+	avg_hold_days    REAL NOT NULL,
+	report_json      TEXT NOT NULL,
+	created_at       TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);`
+
+// InsertBacktestRun persists a completed backtest report so the UI can list
+// and compare historical parameter sweeps.
+func (d *DB) InsertBacktestRun(startDate, endDate string, report *backtest.Report) (int64, error) {
+	if report == nil {
+		return 0, nil
+	}
+	if _, err := d.sql.Exec(createBacktestRunsTableSQL); err != nil {
+		return 0, err
+	}
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := d.sql.Exec(`INSERT INTO backtest_runs (
+		start_date, end_date, starting_balance, final_balance,
+		sharpe_ratio, sortino_ratio, profit_factor, winning_ratio,
+		max_drawdown, avg_hold_days, report_json
+	) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		startDate, endDate, report.StartingBalance, report.FinalBalance,
+		report.SharpeRatio, report.SortinoRatio, report.ProfitFactor, report.WinningRatio,
+		report.MaxDrawdown, report.AvgHoldDays, string(reportJSON),
+	)
+	if err != nil {
+		log.Printf("[DB] InsertBacktestRun: %v", err)
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetBacktestRun retrieves a previously stored backtest report by ID.
+func (d *DB) GetBacktestRun(id int64) (*backtest.Report, error) {
+	if _, err := d.sql.Exec(createBacktestRunsTableSQL); err != nil {
+		return nil, err
+	}
+	var reportJSON string
+	if err := d.sql.QueryRow(`SELECT report_json FROM backtest_runs WHERE id = ?`, id).Scan(&reportJSON); err != nil {
+		return nil, err
+	}
+	var report backtest.Report
+	if err := json.Unmarshal([]byte(reportJSON), &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"encoding/json"
+	"log"
+
+	"eve-flipper/internal/engine"
+)
+
+const createFactorWeightsTableSQL = `
+CREATE TABLE IF NOT EXISTS factor_weights (
+	region_id  INTEGER PRIMARY KEY,
+	weights    TEXT NOT NULL,
+	means      TEXT NOT NULL DEFAULT '[]',
+	stdevs     TEXT NOT NULL DEFAULT '[]',
+	lambda     REAL NOT NULL,
+	samples    INTEGER NOT NULL,
+	updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);`
+
+// SaveFactorWeights upserts the fitted weights for a region, called after
+// each scheduled refit. Means/Stdevs are persisted alongside Weights since
+// EstimateAlpha needs the fit's own normalization to score live candidates
+// on the same scale.
+func (d *DB) SaveFactorWeights(fw engine.FactorWeights) error {
+	if _, err := d.sql.Exec(createFactorWeightsTableSQL); err != nil {
+		return err
+	}
+	weightsJSON, err := json.Marshal(fw.Weights)
+	if err != nil {
+		return err
+	}
+	meansJSON, err := json.Marshal(fw.Means)
+	if err != nil {
+		return err
+	}
+	stdevsJSON, err := json.Marshal(fw.Stdevs)
+	if err != nil {
+		return err
+	}
+	_, err = d.sql.Exec(`
+		INSERT INTO factor_weights (region_id, weights, means, stdevs, lambda, samples, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(region_id) DO UPDATE SET
+			weights = excluded.weights,
+			means = excluded.means,
+			stdevs = excluded.stdevs,
+			lambda = excluded.lambda,
+			samples = excluded.samples,
+			updated_at = excluded.updated_at
+	`, fw.RegionID, string(weightsJSON), string(meansJSON), string(stdevsJSON), fw.Lambda, fw.Samples)
+	if err != nil {
+		log.Printf("[DB] SaveFactorWeights region %d: %v", fw.RegionID, err)
+	}
+	return err
+}
+
+// GetFactorWeights returns the most recently fitted weights for a region,
+// or ok=false if none have been fitted yet.
+func (d *DB) GetFactorWeights(regionID int32) (fw engine.FactorWeights, ok bool) {
+	if _, err := d.sql.Exec(createFactorWeightsTableSQL); err != nil {
+		return fw, false
+	}
+	var weightsJSON, meansJSON, stdevsJSON string
+	err := d.sql.QueryRow(`
+		SELECT region_id, weights, means, stdevs, lambda, samples FROM factor_weights WHERE region_id = ?
+	`, regionID).Scan(&fw.RegionID, &weightsJSON, &meansJSON, &stdevsJSON, &fw.Lambda, &fw.Samples)
+	if err != nil {
+		return engine.FactorWeights{}, false
+	}
+	if err := json.Unmarshal([]byte(weightsJSON), &fw.Weights); err != nil {
+		return engine.FactorWeights{}, false
+	}
+	if err := json.Unmarshal([]byte(meansJSON), &fw.Means); err != nil {
+		return engine.FactorWeights{}, false
+	}
+	if err := json.Unmarshal([]byte(stdevsJSON), &fw.Stdevs); err != nil {
+		return engine.FactorWeights{}, false
+	}
+	return fw, true
+}
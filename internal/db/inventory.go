@@ -0,0 +1,127 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+const createInventoryTableSQL = `
+CREATE TABLE IF NOT EXISTS inventory_positions (
+	contract_id        INTEGER PRIMARY KEY,
+	region_id          INTEGER NOT NULL,
+	cost               REAL NOT NULL,
+	items_json         TEXT NOT NULL,
+	predicted_profit   REAL NOT NULL,
+	predicted_liq_days REAL NOT NULL,
+	realized_profit    REAL NOT NULL DEFAULT 0,
+	unrealized_profit  REAL NOT NULL DEFAULT 0,
+	carry_cost         REAL NOT NULL DEFAULT 0,
+	held_days          REAL NOT NULL DEFAULT 0,
+	closed             INTEGER NOT NULL DEFAULT 0,
+	bought_at          TEXT NOT NULL,
+	closed_at          TEXT
+);`
+
+func (d *DB) ensureInventoryTable() error {
+	_, err := d.sql.Exec(createInventoryTableSQL)
+	return err
+}
+
+// AddInventoryPosition persists a newly-tracked purchase. ContractID is the
+// EVE contract ID, already unique, so it doubles as the primary key rather
+// than a separate autoincrement column.
+func (d *DB) AddInventoryPosition(p *engine.InventoryPosition) error {
+	if err := d.ensureInventoryTable(); err != nil {
+		return err
+	}
+	itemsJSON, err := json.Marshal(p.Items)
+	if err != nil {
+		return err
+	}
+	_, err = d.sql.Exec(`
+		INSERT INTO inventory_positions (
+			contract_id, region_id, cost, items_json,
+			predicted_profit, predicted_liq_days, bought_at
+		) VALUES (?,?,?,?,?,?,?)`,
+		p.ContractID, p.RegionID, p.Cost, string(itemsJSON),
+		p.PredictedExpectedProfit, p.PredictedEstLiquidationDays,
+		p.BoughtAt.Format(time.RFC3339),
+	)
+	return err
+}
+
+// UpdateInventoryPositionState persists refreshed mark-to-market progress
+// for a tracked position, as recomputed by Scanner.UpdateInventory.
+func (d *DB) UpdateInventoryPositionState(p *engine.InventoryPosition) error {
+	if err := d.ensureInventoryTable(); err != nil {
+		return err
+	}
+	itemsJSON, err := json.Marshal(p.Items)
+	if err != nil {
+		return err
+	}
+	_, err = d.sql.Exec(`
+		UPDATE inventory_positions SET
+			items_json = ?, realized_profit = ?, unrealized_profit = ?,
+			carry_cost = ?, held_days = ?
+		 WHERE contract_id = ?`,
+		string(itemsJSON), p.RealizedProfit, p.UnrealizedProfit,
+		p.CarryCostToDate, p.ActualHeldDays, p.ContractID,
+	)
+	return err
+}
+
+// CloseInventoryPosition marks a position closed once every item has sold.
+func (d *DB) CloseInventoryPosition(contractID int32) error {
+	if err := d.ensureInventoryTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		UPDATE inventory_positions SET closed = 1, closed_at = ?
+		 WHERE contract_id = ?`,
+		time.Now().UTC().Format(time.RFC3339), contractID,
+	)
+	return err
+}
+
+// ListOpenInventoryPositions returns every not-yet-closed tracked purchase,
+// reconstructed as engine.InventoryPosition so the tracker can resume
+// watching them after a restart.
+func (d *DB) ListOpenInventoryPositions() ([]*engine.InventoryPosition, error) {
+	if err := d.ensureInventoryTable(); err != nil {
+		return nil, err
+	}
+	rows, err := d.sql.Query(`
+		SELECT contract_id, region_id, cost, items_json,
+			predicted_profit, predicted_liq_days, realized_profit,
+			unrealized_profit, carry_cost, held_days, bought_at
+		  FROM inventory_positions WHERE closed = 0
+		 ORDER BY bought_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*engine.InventoryPosition
+	for rows.Next() {
+		var p engine.InventoryPosition
+		var itemsJSON, boughtAt string
+		if err := rows.Scan(&p.ContractID, &p.RegionID, &p.Cost, &itemsJSON,
+			&p.PredictedExpectedProfit, &p.PredictedEstLiquidationDays, &p.RealizedProfit,
+			&p.UnrealizedProfit, &p.CarryCostToDate, &p.ActualHeldDays, &boughtAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(itemsJSON), &p.Items); err != nil {
+			return nil, err
+		}
+		boughtTime, err := time.Parse(time.RFC3339, boughtAt)
+		if err != nil {
+			return nil, err
+		}
+		p.BoughtAt = boughtTime
+		out = append(out, &p)
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestDB_Inventory_AddListCloseRoundTrip(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	pos := &engine.InventoryPosition{
+		ContractID: 42,
+		RegionID:   10000002,
+		BoughtAt:   time.Now().UTC(),
+		Cost:       1_000_000,
+		Items: []engine.InventoryItem{
+			{TypeID: 34, TypeName: "Tritanium", Quantity: 1000, Remaining: 1000, BuyTimePrice: 5.0},
+		},
+		PredictedExpectedProfit:     200_000,
+		PredictedEstLiquidationDays: 7,
+	}
+	if err := d.AddInventoryPosition(pos); err != nil {
+		t.Fatalf("AddInventoryPosition failed: %v", err)
+	}
+
+	open, err := d.ListOpenInventoryPositions()
+	if err != nil {
+		t.Fatalf("ListOpenInventoryPositions failed: %v", err)
+	}
+	if len(open) != 1 {
+		t.Fatalf("ListOpenInventoryPositions len = %d, want 1", len(open))
+	}
+	if open[0].ContractID != 42 || open[0].Cost != 1_000_000 {
+		t.Errorf("unexpected position: %+v", open[0])
+	}
+	if len(open[0].Items) != 1 || open[0].Items[0].TypeID != 34 {
+		t.Errorf("unexpected items: %+v", open[0].Items)
+	}
+
+	if err := d.CloseInventoryPosition(42); err != nil {
+		t.Fatalf("CloseInventoryPosition failed: %v", err)
+	}
+	open, err = d.ListOpenInventoryPositions()
+	if err != nil {
+		t.Fatalf("ListOpenInventoryPositions after close failed: %v", err)
+	}
+	if len(open) != 0 {
+		t.Fatalf("ListOpenInventoryPositions after close len = %d, want 0", len(open))
+	}
+}
+
+func TestDB_Inventory_RestartRecoversLiquidationProgress(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	pos := &engine.InventoryPosition{
+		ContractID: 7,
+		RegionID:   10000002,
+		BoughtAt:   time.Now().UTC(),
+		Cost:       500_000,
+		Items: []engine.InventoryItem{
+			{TypeID: 35, TypeName: "Pyerite", Quantity: 2000, Remaining: 2000, BuyTimePrice: 1.2},
+		},
+	}
+	if err := d.AddInventoryPosition(pos); err != nil {
+		t.Fatalf("AddInventoryPosition failed: %v", err)
+	}
+
+	// Simulate a sale and an UpdateInventory refresh, persisted the way the
+	// tracker would after each scan.
+	pos.Items[0].Remaining = 500
+	pos.RealizedProfit = 1_800
+	pos.UnrealizedProfit = 300
+	pos.CarryCostToDate = 50
+	pos.ActualHeldDays = 3
+	if err := d.UpdateInventoryPositionState(pos); err != nil {
+		t.Fatalf("UpdateInventoryPositionState failed: %v", err)
+	}
+
+	recovered, err := d.ListOpenInventoryPositions()
+	if err != nil {
+		t.Fatalf("ListOpenInventoryPositions failed: %v", err)
+	}
+	if len(recovered) != 1 {
+		t.Fatalf("ListOpenInventoryPositions len = %d, want 1", len(recovered))
+	}
+	if recovered[0].Items[0].Remaining != 500 {
+		t.Errorf("recovered Remaining = %d, want 500", recovered[0].Items[0].Remaining)
+	}
+	if recovered[0].RealizedProfit != 1_800 {
+		t.Errorf("recovered RealizedProfit = %v, want 1800", recovered[0].RealizedProfit)
+	}
+}
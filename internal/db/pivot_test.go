@@ -0,0 +1,106 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestDB_StationResultsRoundTrip_WithPivotFields(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	id := d.InsertHistory("station", "The Forge", 1, 10_000_000)
+	if id <= 0 {
+		t.Fatal("InsertHistory failed")
+	}
+
+	in := []engine.StationTrade{
+		{
+			TypeID:         34,
+			TypeName:       "Tritanium",
+			BuyPrice:       5.0,
+			SellPrice:      5.4,
+			PivotConfirmed: true,
+			BarsSincePivot: 3,
+			PivotPrice:     4.8,
+		},
+	}
+	d.InsertStationResults(id, in)
+
+	got := d.GetStationResults(id)
+	if len(got) != 1 {
+		t.Fatalf("GetStationResults len = %d, want 1", len(got))
+	}
+	r := got[0]
+	if r.PivotConfirmed != true {
+		t.Errorf("PivotConfirmed = %v, want true", r.PivotConfirmed)
+	}
+	if r.BarsSincePivot != 3 {
+		t.Errorf("BarsSincePivot = %d, want 3", r.BarsSincePivot)
+	}
+	if r.PivotPrice != 4.8 {
+		t.Errorf("PivotPrice = %v, want 4.8", r.PivotPrice)
+	}
+}
+
+func TestDB_FlipResultsRoundTrip_WithPivotFields(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	id := d.InsertHistory("radius", "Jita", 1, 100)
+	if id <= 0 {
+		t.Fatal("InsertHistory failed")
+	}
+
+	in := []engine.FlipResult{
+		{
+			TypeID:         100,
+			TypeName:       "Test Item",
+			BuyPrice:       90,
+			SellPrice:      100,
+			PivotConfirmed: false,
+			BarsSincePivot: 0,
+			PivotPrice:     0,
+		},
+	}
+	d.InsertFlipResults(id, in)
+
+	got := d.GetFlipResults(id)
+	if len(got) != 1 {
+		t.Fatalf("GetFlipResults len = %d, want 1", len(got))
+	}
+	if got[0].PivotConfirmed != false {
+		t.Errorf("PivotConfirmed = %v, want false", got[0].PivotConfirmed)
+	}
+}
+
+func TestDB_Migrate_StationResultsHasPivotColumns(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	rows, err := d.sql.Query("PRAGMA table_info(station_results)")
+	if err != nil {
+		t.Fatalf("PRAGMA table_info(station_results): %v", err)
+	}
+	defer rows.Close()
+
+	have := map[string]bool{}
+	for rows.Next() {
+		var cid int
+		var name, typ string
+		var notNull, pk int
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			t.Fatalf("scan pragma row: %v", err)
+		}
+		have[name] = true
+	}
+
+	for _, col := range []string{"pivot_confirmed", "bars_since_pivot", "pivot_price"} {
+		if !have[col] {
+			t.Errorf("station_results missing column %q", col)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+const createPositionsTableSQL = `
+CREATE TABLE IF NOT EXISTS positions (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	type_id          INTEGER NOT NULL,
+	type_name        TEXT NOT NULL,
+	entry_price      REAL NOT NULL,
+	quantity         INTEGER NOT NULL,
+	tiers_json       TEXT NOT NULL,
+	active_tier      INTEGER NOT NULL,
+	high_water_mark  REAL NOT NULL,
+	closed           INTEGER NOT NULL DEFAULT 0,
+	opened_at        TEXT NOT NULL,
+	closed_at        TEXT
+);`
+
+func (d *DB) ensurePositionsTable() error {
+	_, err := d.sql.Exec(createPositionsTableSQL)
+	return err
+}
+
+// AddPosition persists a newly-entered position and returns its ID.
+func (d *DB) AddPosition(p *engine.TrackedPosition) (int64, error) {
+	if err := d.ensurePositionsTable(); err != nil {
+		return 0, err
+	}
+	tiersJSON, err := json.Marshal(p.Tiers)
+	if err != nil {
+		return 0, err
+	}
+	res, err := d.sql.Exec(`
+		INSERT INTO positions (
+			type_id, type_name, entry_price, quantity, tiers_json,
+			active_tier, high_water_mark, closed, opened_at
+		) VALUES (?,?,?,?,?,?,?,?,?)`,
+		p.TypeID, p.TypeName, p.EntryPrice, p.Quantity, string(tiersJSON),
+		p.ActiveTier, p.HighWaterMark, boolToInt(p.Closed), time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// UpdatePositionState persists the current tier/high-water mark so a
+// restart can resume trailing from where it left off.
+func (d *DB) UpdatePositionState(id int64, activeTier int, highWaterMark float64) error {
+	if err := d.ensurePositionsTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		UPDATE positions SET active_tier = ?, high_water_mark = ?
+		 WHERE id = ?`,
+		activeTier, highWaterMark, id,
+	)
+	return err
+}
+
+// ClosePosition marks a position closed (e.g. once the trailing sell fires
+// and the order fills).
+func (d *DB) ClosePosition(id int64) error {
+	if err := d.ensurePositionsTable(); err != nil {
+		return err
+	}
+	_, err := d.sql.Exec(`
+		UPDATE positions SET closed = 1, closed_at = ?
+		 WHERE id = ?`,
+		time.Now().UTC().Format(time.RFC3339), id,
+	)
+	return err
+}
+
+// ListOpenPositions returns every not-yet-closed position, reconstructed as
+// engine.TrackedPosition so the tracker can resume trailing after a
+// restart.
+func (d *DB) ListOpenPositions() ([]*engine.TrackedPosition, error) {
+	if err := d.ensurePositionsTable(); err != nil {
+		return nil, err
+	}
+	rows, err := d.sql.Query(`
+		SELECT id, type_id, type_name, entry_price, quantity, tiers_json,
+			active_tier, high_water_mark
+		  FROM positions WHERE closed = 0
+		 ORDER BY opened_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*engine.TrackedPosition
+	for rows.Next() {
+		var p engine.TrackedPosition
+		var tiersJSON string
+		if err := rows.Scan(&p.ID, &p.TypeID, &p.TypeName, &p.EntryPrice, &p.Quantity,
+			&tiersJSON, &p.ActiveTier, &p.HighWaterMark); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(tiersJSON), &p.Tiers); err != nil {
+			return nil, err
+		}
+		out = append(out, &p)
+	}
+	return out, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
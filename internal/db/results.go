@@ -23,8 +23,9 @@ func (d *DB) InsertFlipResults(scanID int64, results []engine.FlipResult) {
 		sell_price, sell_station, sell_system_name, sell_system_id,
 		profit_per_unit, margin_percent, units_to_buy,
 		buy_order_remain, sell_order_remain,
-		total_profit, profit_per_jump, buy_jumps, sell_jumps, total_jumps
-	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
+		total_profit, profit_per_jump, buy_jumps, sell_jumps, total_jumps,
+		pivot_confirmed, bars_since_pivot, pivot_price
+	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
 	if err != nil {
 		tx.Rollback()
 		log.Printf("[DB] InsertFlipResults prepare: %v", err)
@@ -40,6 +41,7 @@ func (d *DB) InsertFlipResults(scanID int64, results []engine.FlipResult) {
 			r.ProfitPerUnit, r.MarginPercent, r.UnitsToBuy,
 			r.BuyOrderRemain, r.SellOrderRemain,
 			r.TotalProfit, r.ProfitPerJump, r.BuyJumps, r.SellJumps, r.TotalJumps,
+			r.PivotConfirmed, r.BarsSincePivot, r.PivotPrice,
 		)
 	}
 
@@ -56,7 +58,8 @@ func (d *DB) GetFlipResults(scanID int64) []engine.FlipResult {
 			sell_price, sell_station, sell_system_name, sell_system_id,
 			profit_per_unit, margin_percent, units_to_buy,
 			buy_order_remain, sell_order_remain,
-			total_profit, profit_per_jump, buy_jumps, sell_jumps, total_jumps
+			total_profit, profit_per_jump, buy_jumps, sell_jumps, total_jumps,
+			pivot_confirmed, bars_since_pivot, pivot_price
 		FROM flip_results WHERE scan_id = ?
 	`, scanID)
 	if err != nil {
@@ -74,6 +77,7 @@ func (d *DB) GetFlipResults(scanID int64) []engine.FlipResult {
 			&r.ProfitPerUnit, &r.MarginPercent, &r.UnitsToBuy,
 			&r.BuyOrderRemain, &r.SellOrderRemain,
 			&r.TotalProfit, &r.ProfitPerJump, &r.BuyJumps, &r.SellJumps, &r.TotalJumps,
+			&r.PivotConfirmed, &r.BarsSincePivot, &r.PivotPrice,
 		)
 		results = append(results, r)
 	}
@@ -159,8 +163,9 @@ func (d *DB) InsertStationResults(scanID int64, results []engine.StationTrade) {
 		scan_id, type_id, type_name, buy_price, sell_price,
 		margin, margin_pct, volume, buy_volume, sell_volume,
 		station_id, station_name, cts, sds, period_roi,
-		vwap, pvi, obds, bvs_ratio, dos
-	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
+		vwap, pvi, obds, bvs_ratio, dos, atr, effective_min_margin,
+		pivot_confirmed, bars_since_pivot, pivot_price
+	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
 	if err != nil {
 		tx.Rollback()
 		log.Printf("[DB] InsertStationResults prepare: %v", err)
@@ -173,7 +178,8 @@ func (d *DB) InsertStationResults(scanID int64, results []engine.StationTrade) {
 			scanID, r.TypeID, r.TypeName, r.BuyPrice, r.SellPrice,
 			r.Spread, r.MarginPercent, r.DailyVolume, r.BuyVolume, r.SellVolume,
 			r.StationID, r.StationName, r.CTS, r.SDS, r.PeriodROI,
-			r.VWAP, r.PVI, r.OBDS, r.BvSRatio, r.DOS,
+			r.VWAP, r.PVI, r.OBDS, r.BvSRatio, r.DOS, r.ATR, r.EffectiveMinMargin,
+			r.PivotConfirmed, r.BarsSincePivot, r.PivotPrice,
 		)
 	}
 
@@ -188,7 +194,8 @@ func (d *DB) GetStationResults(scanID int64) []engine.StationTrade {
 		SELECT type_id, type_name, buy_price, sell_price,
 			margin, margin_pct, volume, buy_volume, sell_volume,
 			station_id, station_name, cts, sds, period_roi,
-			vwap, pvi, obds, bvs_ratio, dos
+			vwap, pvi, obds, bvs_ratio, dos, atr, effective_min_margin,
+			pivot_confirmed, bars_since_pivot, pivot_price
 		FROM station_results WHERE scan_id = ?
 	`, scanID)
 	if err != nil {
@@ -203,7 +210,8 @@ func (d *DB) GetStationResults(scanID int64) []engine.StationTrade {
 			&r.TypeID, &r.TypeName, &r.BuyPrice, &r.SellPrice,
 			&r.Spread, &r.MarginPercent, &r.DailyVolume, &r.BuyVolume, &r.SellVolume,
 			&r.StationID, &r.StationName, &r.CTS, &r.SDS, &r.PeriodROI,
-			&r.VWAP, &r.PVI, &r.OBDS, &r.BvSRatio, &r.DOS,
+			&r.VWAP, &r.PVI, &r.OBDS, &r.BvSRatio, &r.DOS, &r.ATR, &r.EffectiveMinMargin,
+			&r.PivotConfirmed, &r.BarsSincePivot, &r.PivotPrice,
 		)
 		results = append(results, r)
 	}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"encoding/json"
+	"log"
+
+	"eve-flipper/internal/engine"
+)
+
+// createStrategyStatsTableSQL creates the strategy_stats table if the main
+// migration set hasn't caught up with this feature yet.
+const createStrategyStatsTableSQL = `
+CREATE TABLE IF NOT EXISTS strategy_stats (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	scan_ids          TEXT NOT NULL,
+	samples           INTEGER NOT NULL,
+	total_profit      REAL NOT NULL,
+	sharpe_ratio      REAL NOT NULL,
+	sortino_ratio     REAL NOT NULL,
+	profit_factor     REAL NOT NULL,
+	winning_ratio     REAL NOT NULL,
+	max_drawdown      REAL NOT NULL,
+	max_drawdown_pct  REAL NOT NULL,
+	cagr              REAL NOT NULL,
+	created_at        TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);`
+
+// ComputeStrategyStats reads realized profit across flip_results,
+// contract_results, and station_results for the given scans (chronological
+// order of scanIDs is treated as the return series) and persists the
+// resulting StrategyStats row so the UI can chart its evolution over time.
+func (d *DB) ComputeStrategyStats(scanIDs []int64) engine.StrategyStats {
+	if len(scanIDs) == 0 {
+		return engine.StrategyStats{}
+	}
+
+	profits := make([]float64, 0, len(scanIDs))
+	for _, scanID := range scanIDs {
+		profits = append(profits, d.scanTotalProfit(scanID))
+	}
+
+	stats := engine.ComputeStrategyStats(scanIDs, profits)
+	d.saveStrategyStats(stats)
+	return stats
+}
+
+// scanTotalProfit sums realized profit across all three result tables for a
+// single scan. A scan only ever populates one of the three tables in
+// practice, but summing all three keeps this correct regardless of scan type.
+func (d *DB) scanTotalProfit(scanID int64) float64 {
+	var total float64
+	for _, q := range []string{
+		"SELECT COALESCE(SUM(total_profit), 0) FROM flip_results WHERE scan_id = ?",
+		"SELECT COALESCE(SUM(profit), 0) FROM contract_results WHERE scan_id = ?",
+		"SELECT COALESCE(SUM(real_profit), 0) FROM station_results WHERE scan_id = ?",
+	} {
+		var sum float64
+		if err := d.sql.QueryRow(q, scanID).Scan(&sum); err == nil {
+			total += sum
+		}
+	}
+	return total
+}
+
+func (d *DB) saveStrategyStats(stats engine.StrategyStats) {
+	if _, err := d.sql.Exec(createStrategyStatsTableSQL); err != nil {
+		log.Printf("[DB] ensure strategy_stats table: %v", err)
+		return
+	}
+
+	scanIDsJSON, _ := json.Marshal(stats.ScanIDs)
+	_, err := d.sql.Exec(`INSERT INTO strategy_stats (
+		scan_ids, samples, total_profit, sharpe_ratio, sortino_ratio,
+		profit_factor, winning_ratio, max_drawdown, max_drawdown_pct, cagr
+	) VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		string(scanIDsJSON), stats.Samples, stats.TotalProfit, stats.SharpeRatio, stats.SortinoRatio,
+		stats.ProfitFactor, stats.WinningRatio, stats.MaxDrawdown, stats.MaxDrawdownPct, stats.CAGR,
+	)
+	if err != nil {
+		log.Printf("[DB] InsertStrategyStats: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// DefaultATRWindow is how many days of true-range history are averaged into
+// ATR when the caller doesn't override it.
+const DefaultATRWindow = 14
+
+// DefaultATRFactor scales ATR-relative volatility into extra required
+// margin: EffectiveMinMargin = baseMinMargin + AtrFactor*(ATR/VWAP).
+const DefaultATRFactor = 1.2
+
+// ComputeATR computes the Average True Range over the trailing window days
+// of market history, using each day's Highest/Lowest and the prior day's
+// Average price as a close proxy (ESI history has no separate close field).
+// Entries need not be pre-sorted; ComputeATR sorts a copy by date.
+func ComputeATR(entries []esi.HistoryEntry, window int) float64 {
+	if len(entries) < 2 {
+		return 0
+	}
+	if window <= 0 {
+		window = DefaultATRWindow
+	}
+
+	sorted := make([]esi.HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	start := len(sorted) - window
+	if start < 1 {
+		start = 1 // need a previous day for the first true range
+	}
+
+	var sum float64
+	var count int
+	for i := start; i < len(sorted); i++ {
+		tr := trueRange(sorted[i].Highest, sorted[i].Lowest, sorted[i-1].Average)
+		sum += tr
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// trueRange is max(high-low, |high-prevClose|, |low-prevClose|).
+func trueRange(high, low, prevClose float64) float64 {
+	tr := high - low
+	if hc := absFloat(high - prevClose); hc > tr {
+		tr = hc
+	}
+	if lc := absFloat(low - prevClose); lc > tr {
+		tr = lc
+	}
+	return tr
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// recentMeanPrice averages the Average price over the most recent days of
+// history (fewer if history is shorter), used to detect a price that has
+// drifted away from its longer-window VWAP baseline.
+func recentMeanPrice(entries []esi.HistoryEntry, days int) float64 {
+	if len(entries) == 0 || days <= 0 {
+		return 0
+	}
+
+	sorted := make([]esi.HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	start := len(sorted) - days
+	if start < 0 {
+		start = 0
+	}
+
+	var sum float64
+	var count int
+	for _, e := range sorted[start:] {
+		if e.Average <= 0 {
+			continue
+		}
+		sum += e.Average
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// EffectiveMinMargin derives the ATR-scaled required margin for an item:
+// the configured base margin plus atrFactor times ATR normalized by VWAP.
+// Falls back to baseMinMargin if vwap isn't usable.
+func EffectiveMinMargin(baseMinMargin, atrFactor, atr, vwap float64) float64 {
+	if vwap <= 0 {
+		return baseMinMargin
+	}
+	if atrFactor <= 0 {
+		atrFactor = DefaultATRFactor
+	}
+	return baseMinMargin + atrFactor*(atr/vwap)
+}
+
+// atrCacheEntry is one cached ATR value with its expiry.
+type atrCacheEntry struct {
+	value     float64
+	expiresAt time.Time
+}
+
+// ATRCache memoizes per-TypeID ATR values for a TTL so a scan doesn't
+// recompute (and refetch history for) the same item repeatedly.
+type ATRCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[int32]atrCacheEntry
+}
+
+// NewATRCache creates an ATRCache with the given TTL (15 minutes if <= 0).
+func NewATRCache(ttl time.Duration) *ATRCache {
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	return &ATRCache{ttl: ttl, entries: make(map[int32]atrCacheEntry)}
+}
+
+// Get returns the cached ATR for typeID if present and not expired.
+func (c *ATRCache) Get(typeID int32) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[typeID]
+	if !ok || time.Now().After(e.expiresAt) {
+		return 0, false
+	}
+	return e.value, true
+}
+
+// Set stores the ATR for typeID, resetting its TTL.
+func (c *ATRCache) Set(typeID int32, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[typeID] = atrCacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// atrForType returns the cached ATR for typeID, computing and caching it
+// from s.History/s.ESI market history on a miss. Returns 0 if no history is
+// available, in which case callers should fall back to the static MinMargin.
+func (s *Scanner) atrForType(regionID, typeID int32, window int, cache *ATRCache) float64 {
+	if cache != nil {
+		if v, ok := cache.Get(typeID); ok {
+			return v
+		}
+	}
+
+	var entries []esi.HistoryEntry
+	if s.History != nil {
+		if cached, ok := s.History.GetMarketHistory(regionID, typeID); ok {
+			entries = cached
+		}
+	}
+	if entries == nil && s.ESI != nil {
+		fetched, err := s.ESI.FetchMarketHistory(regionID, typeID)
+		if err != nil {
+			return 0
+		}
+		entries = fetched
+		if s.History != nil {
+			s.History.SetMarketHistory(regionID, typeID, entries)
+		}
+	}
+
+	atr := ComputeATR(entries, window)
+	if cache != nil {
+		cache.Set(typeID, atr)
+	}
+	return atr
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestComputeATR_FlatPriceIsZero(t *testing.T) {
+	entries := []esi.HistoryEntry{
+		{Date: "2026-01-01", Average: 100, Highest: 100, Lowest: 100},
+		{Date: "2026-01-02", Average: 100, Highest: 100, Lowest: 100},
+		{Date: "2026-01-03", Average: 100, Highest: 100, Lowest: 100},
+	}
+	if got := ComputeATR(entries, 14); got != 0 {
+		t.Fatalf("ComputeATR = %v, want 0", got)
+	}
+}
+
+func TestComputeATR_UsesTrueRangeAcrossGaps(t *testing.T) {
+	entries := []esi.HistoryEntry{
+		{Date: "2026-01-01", Average: 100, Highest: 101, Lowest: 99},
+		{Date: "2026-01-02", Average: 130, Highest: 131, Lowest: 129}, // gapped up overnight
+	}
+	atr := ComputeATR(entries, 14)
+	if atr <= 2 {
+		t.Fatalf("ComputeATR = %v, want > 2 (should reflect the overnight gap)", atr)
+	}
+}
+
+func TestComputeATR_InsufficientHistory(t *testing.T) {
+	entries := []esi.HistoryEntry{{Date: "2026-01-01", Average: 100, Highest: 101, Lowest: 99}}
+	if got := ComputeATR(entries, 14); got != 0 {
+		t.Fatalf("ComputeATR with 1 entry = %v, want 0", got)
+	}
+}
+
+func TestEffectiveMinMargin(t *testing.T) {
+	got := EffectiveMinMargin(10, 1.2, 0.5, 10) // atr/vwap = 0.05
+	want := 10 + 1.2*0.05
+	if got != want {
+		t.Fatalf("EffectiveMinMargin = %v, want %v", got, want)
+	}
+}
+
+func TestEffectiveMinMargin_NoVWAPFallsBackToBase(t *testing.T) {
+	if got := EffectiveMinMargin(10, 1.2, 0.5, 0); got != 10 {
+		t.Fatalf("EffectiveMinMargin with zero VWAP = %v, want 10", got)
+	}
+}
+
+func TestRecentMeanPrice_AveragesMostRecentDays(t *testing.T) {
+	entries := []esi.HistoryEntry{
+		{Date: "2026-01-01", Average: 100},
+		{Date: "2026-01-02", Average: 100},
+		{Date: "2026-01-03", Average: 50},
+		{Date: "2026-01-04", Average: 50},
+		{Date: "2026-01-05", Average: 50},
+	}
+	// Last 3 days: 50, 50, 50 -> 50, regardless of the older 100s.
+	if got := recentMeanPrice(entries, 3); got != 50 {
+		t.Fatalf("recentMeanPrice = %v, want 50", got)
+	}
+}
+
+func TestRecentMeanPrice_ShorterThanWindowUsesWhatExists(t *testing.T) {
+	entries := []esi.HistoryEntry{{Date: "2026-01-01", Average: 80}}
+	if got := recentMeanPrice(entries, 5); got != 80 {
+		t.Fatalf("recentMeanPrice = %v, want 80", got)
+	}
+}
+
+func TestRecentMeanPrice_Empty(t *testing.T) {
+	if got := recentMeanPrice(nil, 5); got != 0 {
+		t.Fatalf("recentMeanPrice(nil) = %v, want 0", got)
+	}
+}
+
+func TestATRCache_GetSetAndExpiry(t *testing.T) {
+	c := NewATRCache(-1) // falls back to default TTL, still fresh immediately
+	if _, ok := c.Get(34); ok {
+		t.Fatal("expected cache miss before Set")
+	}
+	c.Set(34, 1.5)
+	v, ok := c.Get(34)
+	if !ok || v != 1.5 {
+		t.Fatalf("Get(34) = (%v, %v), want (1.5, true)", v, ok)
+	}
+}
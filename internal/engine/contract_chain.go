@@ -0,0 +1,481 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"eve-flipper/internal/esi"
+)
+
+const (
+	// DefaultChainHoldDays bounds how long a chain's relisted leg(s) are
+	// given to sell before ScanContractChains judges their confidence
+	// against the target confidence, mirroring DefaultContractHoldDays.
+	DefaultChainHoldDays = 14
+	// MaxChainItemTypes caps how many distinct item types a buy contract's
+	// signature may have before it's considered for a split chain: every
+	// non-empty proper subset of its types is tried as a candidate
+	// fragment, so this keeps that enumeration bounded.
+	MaxChainItemTypes = 8
+)
+
+// chainItemLine is one typeID/quantity pair within a contract's item-type
+// signature.
+type chainItemLine struct {
+	TypeID   int32
+	Quantity int32
+}
+
+// aggregateItemLines reduces a contract's included, non-blueprint-copy
+// items to one quantity-summed line per type, sorted by typeID so the
+// result is order-independent.
+func aggregateItemLines(items []esi.ContractItem) []chainItemLine {
+	byType := make(map[int32]int32)
+	for _, item := range items {
+		if !item.IsIncluded || item.IsBlueprintCopy {
+			continue
+		}
+		byType[item.TypeID] += item.Quantity
+	}
+	if len(byType) == 0 {
+		return nil
+	}
+	lines := make([]chainItemLine, 0, len(byType))
+	for typeID, qty := range byType {
+		lines = append(lines, chainItemLine{TypeID: typeID, Quantity: qty})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].TypeID < lines[j].TypeID })
+	return lines
+}
+
+// signatureFromLines renders a set of item lines as the canonical
+// multiset key used to match a contract's contents against a relisting of
+// the same bag elsewhere: sorted "typeID:quantity" pairs joined by "|".
+func signatureFromLines(lines []chainItemLine) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		fmt.Fprintf(&b, "%d:%d", l.TypeID, l.Quantity)
+	}
+	return b.String()
+}
+
+// chainListing is one public contract indexed for chain discovery: its
+// item-type signature, where it sits, and how confidently its contents
+// could be re-sold within the chain's hold horizon.
+type chainListing struct {
+	Contract   esi.PublicContract
+	Lines      []chainItemLine
+	RegionID   int32
+	RegionName string
+	SysID      int32
+	VolumeM3   float64 // total m3 of the bag, for freight costing if relisted elsewhere
+	// Confidence is the combined per-item-type fill probability of this
+	// bag's contents selling within the chain's hold horizon (product of
+	// fillProbabilityWithinDays across every line, using the same
+	// dailyVolume-driven estimate ScanContracts uses in estimate mode),
+	// expressed 0-100. It only matters when this listing is used as a
+	// sell leg; a buy leg is an outright purchase of an existing listing,
+	// so it's treated as 100% certain.
+	Confidence float64
+}
+
+// ChainLeg is one hop of a ChainResult: buying an existing contract, or
+// relisting its contents (whole, or split into a fragment) as a new one
+// elsewhere.
+type ChainLeg struct {
+	ContractID int32   `json:"contract_id"`
+	Action     string  `json:"action"` // "buy" or "sell"
+	RegionID   int32   `json:"region_id"`
+	RegionName string  `json:"region_name"`
+	Price      float64 `json:"price"`
+	Confidence float64 `json:"confidence"`
+	Jumps      int     `json:"jumps"` // jumps from the buy leg's station, 0 for the buy leg itself
+}
+
+// ChainResult is a profitable buy -> relist chain discovered by
+// ScanContractChains: buy one contract, then relist its contents -
+// either whole as a single contract or split across two fragment
+// contracts - for more than it cost once sales tax, freight, and carry
+// cost are accounted for.
+type ChainResult struct {
+	Legs           []ChainLeg `json:"legs"`
+	TotalJumps     int        `json:"total_jumps"`
+	Cost           float64    `json:"cost"`
+	GrossProceeds  float64    `json:"gross_proceeds"`
+	FreightCost    float64    `json:"freight_cost"`
+	CarryCost      float64    `json:"carry_cost"`
+	ExpectedProfit float64    `json:"expected_profit"`
+	ExpectedMargin float64    `json:"expected_margin_percent"`
+	// Confidence is the product of every leg's Confidence (each expressed
+	// 0-100), since the chain only pays off if every leg completes.
+	Confidence float64 `json:"confidence"`
+}
+
+// chainHoldDays returns the effective hold horizon for ScanContractChains,
+// mirroring contractHoldDays.
+func chainHoldDays(params ScanParams) int {
+	if params.ChainHoldDays <= 0 {
+		return DefaultChainHoldDays
+	}
+	if params.ChainHoldDays > 180 {
+		return 180
+	}
+	return params.ChainHoldDays
+}
+
+// chainConfidence multiplies every leg's Confidence (0-100 scale) into a
+// single whole-chain probability, also on a 0-100 scale.
+func chainConfidence(legs []ChainLeg) float64 {
+	p := 1.0
+	for _, leg := range legs {
+		p *= leg.Confidence / 100
+	}
+	return p * 100
+}
+
+// chainListingPricedRatio returns the fraction of lines in a candidate
+// listing's item-type signature that have market price data available,
+// mirroring the pricedCount/totalTypes ratio ScanContracts checks against
+// minPricedRatio - a bag where most of the contents can't be priced isn't
+// trustworthy as either a buy or a sell leg.
+func chainListingPricedRatio(lines []chainItemLine, priceData map[int32]*itemPriceData) float64 {
+	if len(lines) == 0 {
+		return 0
+	}
+	var priced int
+	for _, line := range lines {
+		if _, ok := priceData[line.TypeID]; ok {
+			priced++
+		}
+	}
+	return float64(priced) / float64(len(lines))
+}
+
+// filterChainResults drops chains whose confidence or margin fall outside
+// the scan's configured bounds: below targetConfidence or minMargin (too
+// unreliable/unprofitable to act on), or above maxMargin (an implausibly
+// high margin is almost always a scam or pricing error, same as
+// ScanContracts' own maxContractMargin check).
+func filterChainResults(results []ChainResult, targetConfidence, minMargin, maxMargin float64) []ChainResult {
+	var filtered []ChainResult
+	for _, r := range results {
+		if r.Confidence < targetConfidence {
+			continue
+		}
+		if r.ExpectedMargin < minMargin {
+			continue
+		}
+		if r.ExpectedMargin > maxMargin {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// jumpsFunc resolves the jump distance between two solar systems, letting
+// the chain search run against a real Scanner or, in tests, a plain map.
+type jumpsFunc func(fromSysID, toSysID int32) int
+
+// findTwoLegChains looks for item-type signatures listed in more than one
+// location and pairs the cheapest listing (the buy leg) against every
+// pricier listing of the same signature elsewhere (the sell leg), keeping
+// any pair whose proceeds after tax, freight, and carry cost still clear a
+// profit.
+func findTwoLegChains(bySignature map[string][]chainListing, jumps jumpsFunc, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate float64, holdDays int) []ChainResult {
+	var results []ChainResult
+	for _, listings := range bySignature {
+		if len(listings) < 2 {
+			continue
+		}
+		for i, buy := range listings {
+			for j, sell := range listings {
+				if i == j || sell.Contract.Price <= buy.Contract.Price {
+					continue
+				}
+				if chain := buildTwoLegChain(buy, sell, jumps, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate, holdDays); chain != nil {
+					results = append(results, *chain)
+				}
+			}
+		}
+	}
+	return results
+}
+
+func buildTwoLegChain(buy, sell chainListing, jumps jumpsFunc, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate float64, holdDays int) *ChainResult {
+	j := jumps(buy.SysID, sell.SysID)
+	freightCost := float64(j) * sell.VolumeM3 * freightISKPerJumpPerM3
+	gross := sell.Contract.Price * sellValueMult
+	carryCost := buy.Contract.Price * dailyCarryRate * float64(holdDays)
+	profit := gross - freightCost - carryCost - buy.Contract.Price
+	if profit <= 0 {
+		return nil
+	}
+
+	legs := []ChainLeg{
+		{ContractID: buy.Contract.ContractID, Action: "buy", RegionID: buy.RegionID, RegionName: buy.RegionName, Price: buy.Contract.Price, Confidence: 100},
+		{ContractID: sell.Contract.ContractID, Action: "sell", RegionID: sell.RegionID, RegionName: sell.RegionName, Price: sell.Contract.Price, Confidence: sanitizeFloat(sell.Confidence), Jumps: j},
+	}
+	return &ChainResult{
+		Legs:           legs,
+		TotalJumps:     j,
+		Cost:           buy.Contract.Price,
+		GrossProceeds:  gross,
+		FreightCost:    sanitizeFloat(freightCost),
+		CarryCost:      sanitizeFloat(carryCost),
+		ExpectedProfit: sanitizeFloat(profit),
+		ExpectedMargin: safeDiv(profit, buy.Contract.Price) * 100,
+		Confidence:     sanitizeFloat(chainConfidence(legs)),
+	}
+}
+
+// findSplitChains looks for a buy contract whose contents split cleanly
+// into two fragments that are each, coincidentally, already listed
+// elsewhere as their own contract - buy the bulk lot, break it up, relist
+// the halves. Only contracts with at most MaxChainItemTypes distinct item
+// types are considered, since every non-empty proper subset of types is
+// tried as a candidate fragment (a bitmask over the low half of subsets,
+// since trying both a subset and its complement from each side would just
+// find every split twice).
+func findSplitChains(buyListings []chainListing, bySignature map[string][]chainListing, jumps jumpsFunc, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate float64, holdDays int) []ChainResult {
+	var results []ChainResult
+	for _, buy := range buyListings {
+		n := len(buy.Lines)
+		if n < 2 || n > MaxChainItemTypes {
+			continue
+		}
+		for mask := 1; mask < (1 << (n - 1)); mask++ {
+			var fragA, fragB []chainItemLine
+			for idx, line := range buy.Lines {
+				if mask&(1<<uint(idx)) != 0 {
+					fragA = append(fragA, line)
+				} else {
+					fragB = append(fragB, line)
+				}
+			}
+			sigA := signatureFromLines(fragA)
+			sigB := signatureFromLines(fragB)
+			for _, sellA := range bySignature[sigA] {
+				if sellA.Contract.ContractID == buy.Contract.ContractID {
+					continue
+				}
+				for _, sellB := range bySignature[sigB] {
+					if sellB.Contract.ContractID == buy.Contract.ContractID || sellB.Contract.ContractID == sellA.Contract.ContractID {
+						continue
+					}
+					if chain := buildSplitChain(buy, sellA, sellB, jumps, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate, holdDays); chain != nil {
+						results = append(results, *chain)
+					}
+				}
+			}
+		}
+	}
+	return results
+}
+
+func buildSplitChain(buy, sellA, sellB chainListing, jumps jumpsFunc, sellValueMult, freightISKPerJumpPerM3, dailyCarryRate float64, holdDays int) *ChainResult {
+	jA := jumps(buy.SysID, sellA.SysID)
+	jB := jumps(buy.SysID, sellB.SysID)
+	freightCost := float64(jA)*sellA.VolumeM3*freightISKPerJumpPerM3 + float64(jB)*sellB.VolumeM3*freightISKPerJumpPerM3
+	gross := (sellA.Contract.Price + sellB.Contract.Price) * sellValueMult
+	carryCost := buy.Contract.Price * dailyCarryRate * float64(holdDays)
+	profit := gross - freightCost - carryCost - buy.Contract.Price
+	if profit <= 0 {
+		return nil
+	}
+
+	legs := []ChainLeg{
+		{ContractID: buy.Contract.ContractID, Action: "buy", RegionID: buy.RegionID, RegionName: buy.RegionName, Price: buy.Contract.Price, Confidence: 100},
+		{ContractID: sellA.Contract.ContractID, Action: "sell", RegionID: sellA.RegionID, RegionName: sellA.RegionName, Price: sellA.Contract.Price, Confidence: sanitizeFloat(sellA.Confidence), Jumps: jA},
+		{ContractID: sellB.Contract.ContractID, Action: "sell", RegionID: sellB.RegionID, RegionName: sellB.RegionName, Price: sellB.Contract.Price, Confidence: sanitizeFloat(sellB.Confidence), Jumps: jB},
+	}
+	return &ChainResult{
+		Legs:           legs,
+		TotalJumps:     jA + jB,
+		Cost:           buy.Contract.Price,
+		GrossProceeds:  gross,
+		FreightCost:    sanitizeFloat(freightCost),
+		CarryCost:      sanitizeFloat(carryCost),
+		ExpectedProfit: sanitizeFloat(profit),
+		ExpectedMargin: safeDiv(profit, buy.Contract.Price) * 100,
+		Confidence:     sanitizeFloat(chainConfidence(legs)),
+	}
+}
+
+// ScanContractChains discovers profitable buy-then-relist chains of
+// public contracts: buy one contract (a bulk or mixed bag), then within
+// chainHoldDays re-list its contents as a single repackaged contract
+// elsewhere, or split it into two fragment contracts, for more than it
+// cost. Item-type signatures (a multiset of typeID -> quantity) are used
+// to match a buy contract's contents against other currently-listed
+// contracts that are, or can be split into, the same bag, since this is
+// the only way to recognize "the same goods, relisted" across ESI's
+// public contract feed. Candidate chains of length 2 (buy, relist whole)
+// and 3 (buy, relist as two fragments) are searched, confidence-gated and
+// sorted by ExpectedProfit/TotalJumps so they're directly comparable
+// against ScanContracts' single-leg results.
+func (s *Scanner) ScanContractChains(params ScanParams, progress func(string)) ([]ChainResult, error) {
+	progress("Finding systems within radius...")
+	var buySystems map[int32]int
+	if params.MinRouteSecurity > 0 {
+		buySystems = s.SDE.Universe.SystemsWithinRadiusMinSecurity(params.CurrentSystemID, params.BuyRadius, params.MinRouteSecurity)
+	} else {
+		buySystems = s.SDE.Universe.SystemsWithinRadius(params.CurrentSystemID, params.BuyRadius)
+	}
+	buyRegions := s.SDE.Universe.RegionsInSet(buySystems)
+
+	progress(fmt.Sprintf("Fetching contracts + market data from %d regions...", len(buyRegions)))
+
+	var allContracts []esi.PublicContract
+	for regionID := range buyRegions {
+		contracts, err := s.ESI.FetchRegionContractsCached(s.ContractsCache, regionID)
+		if err != nil {
+			log.Printf("[DEBUG] ScanContractChains: failed to fetch contracts for region %d: %v", regionID, err)
+			continue
+		}
+		allContracts = append(allContracts, contracts...)
+	}
+
+	sellOrders := s.fetchOrders(buyRegions, "sell", buySystems)
+	marketLocationSystems := make(map[int64]int32, len(sellOrders))
+	for _, o := range sellOrders {
+		if o.LocationID == 0 || o.SystemID == 0 {
+			continue
+		}
+		if _, exists := marketLocationSystems[o.LocationID]; !exists {
+			marketLocationSystems[o.LocationID] = o.SystemID
+		}
+	}
+	priceData := make(map[int32]*itemPriceData)
+	for _, o := range sellOrders {
+		pd, ok := priceData[o.TypeID]
+		if !ok {
+			pd = &itemPriceData{MinSellPrice: o.Price}
+			priceData[o.TypeID] = pd
+		}
+		if o.Price < pd.MinSellPrice {
+			pd.MinSellPrice = o.Price
+		}
+		pd.TotalSellVol += o.VolumeRemain
+		pd.SellOrderCnt++
+	}
+
+	minContractPrice, maxContractMargin, minPricedRatio := getContractFilters(params)
+
+	var candidates []esi.PublicContract
+	for _, c := range allContracts {
+		if c.Type != "item_exchange" || c.IsExpired() || c.Price < minContractPrice {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	contractIDs := make([]int32, len(candidates))
+	for i, c := range candidates {
+		contractIDs[i] = c.ContractID
+	}
+	contractItems := s.ESI.FetchContractItemsBatch(contractIDs, s.ContractItemsCache, func(done, total int) {
+		progress(fmt.Sprintf("Fetching contract items %d/%d...", done, total))
+	})
+
+	holdDays := chainHoldDays(params)
+	bySignature := make(map[string][]chainListing)
+	var allListings []chainListing
+	for _, c := range candidates {
+		items, ok := contractItems[c.ContractID]
+		if !ok || len(items) == 0 {
+			continue
+		}
+		lines := aggregateItemLines(items)
+		sig := signatureFromLines(lines)
+		if sig == "" {
+			continue
+		}
+		if chainListingPricedRatio(lines, priceData) < minPricedRatio {
+			continue // can't reliably price most of this bag
+		}
+
+		sysID := s.locationToSystem(c.StartLocationID, marketLocationSystems)
+		regionID := int32(0)
+		regionName := ""
+		if sysID != 0 {
+			if sys, ok := s.SDE.Systems[sysID]; ok {
+				regionID = sys.RegionID
+				regionName = s.regionName(sys.RegionID)
+			}
+		}
+
+		var volumeM3 float64
+		confidence := 1.0
+		for _, line := range lines {
+			typeName, ok := s.SDE.Types[line.TypeID]
+			if ok {
+				volumeM3 += typeName.Volume * float64(line.Quantity)
+			}
+			pd, ok := priceData[line.TypeID]
+			if !ok {
+				confidence = 0
+				continue
+			}
+			dailyVol := effectiveDailyVolume(pd)
+			fillDays := estimateFillDays(line.Quantity, dailyVol)
+			confidence *= fillProbabilityWithinDays(fillDays, float64(holdDays))
+		}
+
+		listing := chainListing{
+			Contract:   c,
+			Lines:      lines,
+			RegionID:   regionID,
+			RegionName: regionName,
+			SysID:      sysID,
+			VolumeM3:   volumeM3,
+			Confidence: confidence * 100,
+		}
+		allListings = append(allListings, listing)
+		bySignature[sig] = append(bySignature[sig], listing)
+	}
+
+	jumps := func(fromSysID, toSysID int32) int {
+		if fromSysID == 0 || toSysID == 0 || fromSysID == toSysID {
+			return 0
+		}
+		return s.jumpsBetweenWithSecurity(fromSysID, toSysID, params.MinRouteSecurity)
+	}
+
+	sellValueMult := contractSellValueMultiplier(params)
+	targetConfidence := contractTargetConfidence(params)
+
+	var results []ChainResult
+	results = append(results, findTwoLegChains(bySignature, jumps, sellValueMult, params.FreightISKPerJumpPerM3, ContractDailyCarryRate, holdDays)...)
+	results = append(results, findSplitChains(allListings, bySignature, jumps, sellValueMult, params.FreightISKPerJumpPerM3, ContractDailyCarryRate, holdDays)...)
+
+	filtered := filterChainResults(results, targetConfidence, params.MinMargin, maxContractMargin)
+
+	sort.Slice(filtered, func(i, j int) bool {
+		li, lj := filtered[i].ExpectedProfit, filtered[j].ExpectedProfit
+		if filtered[i].TotalJumps > 0 {
+			li = filtered[i].ExpectedProfit / float64(filtered[i].TotalJumps)
+		}
+		if filtered[j].TotalJumps > 0 {
+			lj = filtered[j].ExpectedProfit / float64(filtered[j].TotalJumps)
+		}
+		return li > lj
+	})
+	if len(filtered) > MaxUnlimitedResults {
+		filtered = filtered[:MaxUnlimitedResults]
+	}
+
+	progress(fmt.Sprintf("Found %d profitable contract chains", len(filtered)))
+	return filtered, nil
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func flatJumps(dist int) jumpsFunc {
+	return func(fromSysID, toSysID int32) int {
+		if fromSysID == toSysID {
+			return 0
+		}
+		return dist
+	}
+}
+
+func TestAggregateItemLinesAndSignature(t *testing.T) {
+	items := []esi.ContractItem{
+		{TypeID: 34, Quantity: 100, IsIncluded: true},
+		{TypeID: 35, Quantity: 50, IsIncluded: true},
+		{TypeID: 34, Quantity: 25, IsIncluded: true},  // split across two rows, should sum
+		{TypeID: 999, Quantity: 1, IsIncluded: false}, // buyer-provided, excluded
+		{TypeID: 998, Quantity: 1, IsIncluded: true, IsBlueprintCopy: true},
+	}
+	lines := aggregateItemLines(items)
+	if len(lines) != 2 {
+		t.Fatalf("lines = %+v, want 2 aggregated lines", lines)
+	}
+	if lines[0].TypeID != 34 || lines[0].Quantity != 125 {
+		t.Errorf("lines[0] = %+v, want {34 125}", lines[0])
+	}
+
+	// Signature must be identical regardless of the original row order.
+	reordered := aggregateItemLines([]esi.ContractItem{
+		{TypeID: 35, Quantity: 50, IsIncluded: true},
+		{TypeID: 34, Quantity: 125, IsIncluded: true},
+	})
+	if signatureFromLines(lines) != signatureFromLines(reordered) {
+		t.Errorf("signatures differ for the same bag listed in a different row order")
+	}
+}
+
+func TestFindTwoLegChains_ProfitableRelistWins(t *testing.T) {
+	sig := signatureFromLines([]chainItemLine{{TypeID: 34, Quantity: 100}})
+	bySignature := map[string][]chainListing{
+		sig: {
+			{Contract: esi.PublicContract{ContractID: 1, Price: 1_000_000}, RegionID: 1, SysID: 1},
+			{Contract: esi.PublicContract{ContractID: 2, Price: 1_500_000}, RegionID: 2, SysID: 2, Confidence: 90},
+		},
+	}
+
+	chains := findTwoLegChains(bySignature, flatJumps(3), 0.95, 100, 0.001, 14)
+	if len(chains) != 1 {
+		t.Fatalf("chains = %+v, want exactly one profitable pairing", chains)
+	}
+	c := chains[0]
+	if c.Legs[0].Action != "buy" || c.Legs[0].ContractID != 1 {
+		t.Errorf("Legs[0] = %+v, want the buy leg first", c.Legs[0])
+	}
+	if c.Legs[1].Action != "sell" || c.Legs[1].ContractID != 2 {
+		t.Errorf("Legs[1] = %+v, want the sell leg second", c.Legs[1])
+	}
+	if c.TotalJumps != 3 {
+		t.Errorf("TotalJumps = %d, want 3", c.TotalJumps)
+	}
+	// Confidence is the buy leg (100) * the sell leg (90) / 100 = 90.
+	if c.Confidence != 90 {
+		t.Errorf("Confidence = %v, want 90", c.Confidence)
+	}
+}
+
+func TestFindTwoLegChains_RejectsUnprofitableRelist(t *testing.T) {
+	sig := signatureFromLines([]chainItemLine{{TypeID: 34, Quantity: 100}})
+	bySignature := map[string][]chainListing{
+		sig: {
+			{Contract: esi.PublicContract{ContractID: 1, Price: 1_000_000}, RegionID: 1, SysID: 1},
+			{Contract: esi.PublicContract{ContractID: 2, Price: 1_050_000}, RegionID: 2, SysID: 2, Confidence: 90, VolumeM3: 10000},
+		},
+	}
+
+	// A long haul on a bulky bag eats the thin 50k margin entirely.
+	chains := findTwoLegChains(bySignature, flatJumps(20), 0.95, 50, 0.001, 14)
+	if len(chains) != 0 {
+		t.Fatalf("chains = %+v, want no chain once freight exceeds the margin", chains)
+	}
+}
+
+func TestFindSplitChains_MatchesFragmentsAcrossRegions(t *testing.T) {
+	buyLines := []chainItemLine{{TypeID: 34, Quantity: 100}, {TypeID: 35, Quantity: 50}}
+	buy := chainListing{
+		Contract: esi.PublicContract{ContractID: 1, Price: 1_000_000},
+		Lines:    buyLines,
+		SysID:    1,
+	}
+	sigA := signatureFromLines([]chainItemLine{{TypeID: 34, Quantity: 100}})
+	sigB := signatureFromLines([]chainItemLine{{TypeID: 35, Quantity: 50}})
+	bySignature := map[string][]chainListing{
+		sigA: {{Contract: esi.PublicContract{ContractID: 2, Price: 700_000}, SysID: 2, Confidence: 80}},
+		sigB: {{Contract: esi.PublicContract{ContractID: 3, Price: 500_000}, SysID: 3, Confidence: 80}},
+	}
+
+	chains := findSplitChains([]chainListing{buy}, bySignature, flatJumps(2), 0.95, 10, 0.001, 14)
+	if len(chains) != 1 {
+		t.Fatalf("chains = %+v, want exactly one 3-leg split chain", chains)
+	}
+	c := chains[0]
+	if len(c.Legs) != 3 {
+		t.Fatalf("Legs = %+v, want 3 legs (buy + two fragment sells)", c.Legs)
+	}
+	if c.TotalJumps != 4 {
+		t.Errorf("TotalJumps = %d, want 4 (2 fragments x 2 jumps)", c.TotalJumps)
+	}
+}
+
+func TestChainListingPricedRatio(t *testing.T) {
+	priceData := map[int32]*itemPriceData{
+		34: {MinSellPrice: 100},
+	}
+	lines := []chainItemLine{{TypeID: 34, Quantity: 100}, {TypeID: 35, Quantity: 50}}
+	if got := chainListingPricedRatio(lines, priceData); got != 0.5 {
+		t.Errorf("chainListingPricedRatio = %v, want 0.5 (1 of 2 lines priced)", got)
+	}
+	if got := chainListingPricedRatio(nil, priceData); got != 0 {
+		t.Errorf("chainListingPricedRatio(nil) = %v, want 0", got)
+	}
+}
+
+func TestFilterChainResults_RejectsLowConfidenceLowMarginAndScamMargin(t *testing.T) {
+	results := []ChainResult{
+		{ExpectedMargin: 20, Confidence: 95},  // kept
+		{ExpectedMargin: 20, Confidence: 50},  // below target confidence
+		{ExpectedMargin: 1, Confidence: 95},   // below min margin
+		{ExpectedMargin: 500, Confidence: 95}, // above max margin, likely a scam
+	}
+
+	filtered := filterChainResults(results, 80, 5, 100)
+	if len(filtered) != 1 {
+		t.Fatalf("filtered = %+v, want exactly the one chain within bounds", filtered)
+	}
+	if filtered[0].ExpectedMargin != 20 {
+		t.Errorf("filtered[0].ExpectedMargin = %v, want 20", filtered[0].ExpectedMargin)
+	}
+}
+
+func TestFindSplitChains_SkipsContractsAboveMaxItemTypes(t *testing.T) {
+	lines := make([]chainItemLine, MaxChainItemTypes+1)
+	for i := range lines {
+		lines[i] = chainItemLine{TypeID: int32(i + 1), Quantity: 1}
+	}
+	buy := chainListing{Contract: esi.PublicContract{ContractID: 1, Price: 1}, Lines: lines, SysID: 1}
+
+	chains := findSplitChains([]chainListing{buy}, map[string][]chainListing{}, flatJumps(1), 1, 0, 0, 14)
+	if len(chains) != 0 {
+		t.Errorf("chains = %+v, want none once the bag exceeds MaxChainItemTypes", chains)
+	}
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// Hub region IDs for the five major trade hubs HedgeRegions commonly
+// targets, alongside their representative trade-hub station (used to
+// compute freight distance into that region).
+const (
+	RegionTheForge   int32 = 10000002 // Jita
+	RegionDomain     int32 = 10000043 // Amarr
+	RegionHeimatar   int32 = 10000030 // Rens
+	RegionSinqLaison int32 = 10000032 // Dodixie
+	RegionMetropolis int32 = 10000042 // Hek
+)
+
+// hedgeHubStations maps a hedge-hub region to its representative
+// trade-hub station, used to resolve jumps-from-contract-station for
+// freight costing.
+var hedgeHubStations = map[int32]int64{
+	RegionTheForge:   60003760, // Jita IV - Moon 4 - Caldari Navy Assembly Plant
+	RegionDomain:     60008494, // Amarr VIII (Oris) - Emperor Family Academy
+	RegionHeimatar:   60004588, // Rens VI - Moon 8 - Brutor Tribe Treasury
+	RegionSinqLaison: 60011866, // Dodixie IX - Moon 20 - Federation Navy Assembly Plant
+	RegionMetropolis: 60005686, // Hek VIII - Moon 12 - Boundless Creation Factory
+}
+
+// regionHedgeBook is one hedge region's buy-order book plus the jumps from
+// the contract's station to that region's trade hub, needed to price
+// freight for anything routed there.
+type regionHedgeBook struct {
+	regionID int32
+	orders   []esi.MarketOrder
+	jumps    int
+}
+
+// RegionHedgeFill is one region's contribution to a HedgedExecutionPlan:
+// how much quantity it absorbed, at what net proceeds, and what hauling it
+// there cost. RegionID 0 denotes the local sell-radius book (no freight).
+type RegionHedgeFill struct {
+	RegionID      int32   `json:"region_id"`
+	Quantity      int32   `json:"quantity"`
+	GrossProceeds float64 `json:"gross_proceeds"`
+	FreightCost   float64 `json:"freight_cost"`
+	Jumps         int     `json:"jumps"`
+}
+
+// HedgedExecutionPlan is a multi-region instant-liquidation plan covering
+// one contract item type: the local sell-radius buy book is blended with
+// configured hub-region buy books, filling the requested quantity in
+// descending net-price order.
+type HedgedExecutionPlan struct {
+	CanFill bool
+	// ExpectedPrice is the blended raw (pre-tax) proceeds per unit across
+	// every region that contributed a fill, mirroring ComputeExecutionPlan's
+	// ExpectedPrice so callers can keep accumulating marketValue the same
+	// way regardless of whether hedging is enabled.
+	ExpectedPrice float64
+	// FreightCost is the total ISK spent hauling the hedge-region portions
+	// of this fill to their respective hubs; it is not reflected in
+	// ExpectedPrice and must be deducted from profit directly, since sales
+	// tax (applied uniformly later) does not apply to freight.
+	FreightCost float64
+	Breakdown   []RegionHedgeFill
+}
+
+// hedgeLot is one buy order flattened for the greedy fill, tagged with the
+// net-of-tax-and-freight price it actually nets per unit.
+type hedgeLot struct {
+	regionID    int32
+	jumps       int
+	price       float64 // raw order price, pre-tax
+	netPrice    float64 // price*(1-tax) - freight, used for fill ordering
+	freightUnit float64 // freight ISK per unit, 0 for the local book
+	remaining   int32
+}
+
+// computeHedgedExecutionPlan greedily fills quantity across localBook (the
+// sell-radius buy book, zero freight) and hedgeBooks (hub-region buy
+// books), always draining whichever order currently offers the best net
+// price per unit: order price × (1 - salesTaxPercent/100) − jumps ×
+// itemVolume × freightISKPerJumpPerM3. It rejects the fill (CanFill=false)
+// if the combined books can't cover the full quantity, mirroring the
+// invariant local-only instant liquidation already enforces.
+func computeHedgedExecutionPlan(localBook []esi.MarketOrder, hedgeBooks []regionHedgeBook, quantity int32, itemVolume, salesTaxPercent, freightISKPerJumpPerM3 float64) HedgedExecutionPlan {
+	if quantity <= 0 {
+		return HedgedExecutionPlan{CanFill: true}
+	}
+
+	taxMult := 1.0 - salesTaxPercent/100
+	if taxMult < 0 {
+		taxMult = 0
+	}
+
+	lots := make([]hedgeLot, 0, len(localBook))
+	for _, o := range localBook {
+		if o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		lots = append(lots, hedgeLot{
+			price:     o.Price,
+			netPrice:  o.Price * taxMult,
+			remaining: o.VolumeRemain,
+		})
+	}
+	for _, hb := range hedgeBooks {
+		freightUnit := float64(hb.jumps) * itemVolume * freightISKPerJumpPerM3
+		for _, o := range hb.orders {
+			if o.VolumeRemain <= 0 || o.Price <= 0 {
+				continue
+			}
+			lots = append(lots, hedgeLot{
+				regionID:    hb.regionID,
+				jumps:       hb.jumps,
+				price:       o.Price,
+				netPrice:    o.Price*taxMult - freightUnit,
+				freightUnit: freightUnit,
+				remaining:   o.VolumeRemain,
+			})
+		}
+	}
+
+	sort.Slice(lots, func(i, j int) bool { return lots[i].netPrice > lots[j].netPrice })
+
+	fillsByRegion := make(map[int32]*RegionHedgeFill)
+	order := []int32{} // first-seen order, for deterministic Breakdown output
+	var remaining = quantity
+	var grossProceeds, freightCost float64
+
+	for _, lot := range lots {
+		if remaining <= 0 {
+			break
+		}
+		if lot.netPrice <= 0 {
+			continue // not worth filling: tax+freight eats the whole price
+		}
+		take := lot.remaining
+		if take > remaining {
+			take = remaining
+		}
+		remaining -= take
+
+		fill, ok := fillsByRegion[lot.regionID]
+		if !ok {
+			fill = &RegionHedgeFill{RegionID: lot.regionID, Jumps: lot.jumps}
+			fillsByRegion[lot.regionID] = fill
+			order = append(order, lot.regionID)
+		}
+		fill.Quantity += take
+		fill.GrossProceeds += lot.price * float64(take)
+		fill.FreightCost += lot.freightUnit * float64(take)
+
+		grossProceeds += lot.price * float64(take)
+		freightCost += lot.freightUnit * float64(take)
+	}
+
+	if remaining > 0 {
+		return HedgedExecutionPlan{CanFill: false}
+	}
+
+	breakdown := make([]RegionHedgeFill, 0, len(order))
+	for _, regionID := range order {
+		breakdown = append(breakdown, *fillsByRegion[regionID])
+	}
+
+	return HedgedExecutionPlan{
+		CanFill:       true,
+		ExpectedPrice: grossProceeds / float64(quantity),
+		FreightCost:   freightCost,
+		Breakdown:     breakdown,
+	}
+}
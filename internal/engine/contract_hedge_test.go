@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestComputeHedgedExecutionPlan_LocalOnlyFill(t *testing.T) {
+	local := []esi.MarketOrder{{Price: 100, VolumeRemain: 10}}
+	plan := computeHedgedExecutionPlan(local, nil, 10, 1, 5, 1000)
+	if !plan.CanFill {
+		t.Fatalf("expected CanFill=true")
+	}
+	if plan.ExpectedPrice != 100 {
+		t.Errorf("ExpectedPrice = %v, want 100", plan.ExpectedPrice)
+	}
+	if plan.FreightCost != 0 {
+		t.Errorf("FreightCost = %v, want 0 for a fully local fill", plan.FreightCost)
+	}
+	if len(plan.Breakdown) != 1 || plan.Breakdown[0].RegionID != 0 {
+		t.Fatalf("Breakdown = %+v, want a single local (region 0) entry", plan.Breakdown)
+	}
+}
+
+func TestComputeHedgedExecutionPlan_PrefersBetterNetPriceRegardlessOfSource(t *testing.T) {
+	// Local book only covers half the quantity at a mediocre price; a hedge
+	// region offers a far better price that, even after tax and a short
+	// haul, still beats the remaining local price.
+	local := []esi.MarketOrder{{Price: 90, VolumeRemain: 5}}
+	hedge := []regionHedgeBook{{
+		regionID: RegionTheForge,
+		jumps:    2,
+		orders:   []esi.MarketOrder{{Price: 200, VolumeRemain: 5}},
+	}}
+
+	plan := computeHedgedExecutionPlan(local, hedge, 10, 1, 10, 5)
+	if !plan.CanFill {
+		t.Fatalf("expected CanFill=true")
+	}
+	if len(plan.Breakdown) != 2 {
+		t.Fatalf("Breakdown = %+v, want fills from both the local book and the hedge region", plan.Breakdown)
+	}
+	// Hedge net price: 200*0.9 - 2*1*5 = 170; local net price: 90*0.9 = 81.
+	// Hedge region should be drained first.
+	if plan.Breakdown[0].RegionID != RegionTheForge {
+		t.Errorf("Breakdown[0].RegionID = %v, want the hedge region to fill first", plan.Breakdown[0].RegionID)
+	}
+	if plan.FreightCost != 2*1*5*5 {
+		t.Errorf("FreightCost = %v, want %v", plan.FreightCost, 2*1*5*5)
+	}
+}
+
+func TestComputeHedgedExecutionPlan_RejectsWhenCombinedBooksCantFill(t *testing.T) {
+	local := []esi.MarketOrder{{Price: 100, VolumeRemain: 3}}
+	hedge := []regionHedgeBook{{
+		regionID: RegionDomain,
+		jumps:    1,
+		orders:   []esi.MarketOrder{{Price: 100, VolumeRemain: 3}},
+	}}
+
+	plan := computeHedgedExecutionPlan(local, hedge, 10, 1, 5, 100)
+	if plan.CanFill {
+		t.Fatalf("expected CanFill=false when combined books can't cover quantity")
+	}
+}
+
+func TestComputeHedgedExecutionPlan_ZeroQuantity(t *testing.T) {
+	plan := computeHedgedExecutionPlan(nil, nil, 0, 1, 5, 100)
+	if !plan.CanFill {
+		t.Errorf("expected CanFill=true for zero quantity")
+	}
+}
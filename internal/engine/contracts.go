@@ -34,6 +34,16 @@ const (
 	ContractConservativePriceHaircut = 0.03
 	// ContractDailyCarryRate models opportunity/carry cost of locked capital per day.
 	ContractDailyCarryRate = 0.001
+	// DefaultATRHaircutFactor (k) scales an item's ATRPercent into its
+	// conservative-price haircut: max(ContractConservativePriceHaircut,
+	// k*ATRPercent). Volatile items get marked down harder than the flat
+	// 3% default.
+	DefaultATRHaircutFactor = 1.5
+	// DriftRejectATRMultiple is how many ATRs an item's recent 5-day mean
+	// price may have fallen below its VWAP baseline before it's treated as
+	// unpriceable rather than trusted — catches "the item just crashed"
+	// scams that the static 50%-of-VWAP bait filter misses.
+	DriftRejectATRMultiple = 2.0
 )
 
 // getContractFilters returns effective filter values, using defaults if params are 0.
@@ -150,6 +160,10 @@ type itemPriceData struct {
 	VWAP         float64 // Volume-weighted average price from history (0 if no history)
 	DailyVolume  float64 // Average daily trading volume
 	HasHistory   bool    // Whether we have reliable history data
+
+	ATR           float64 // Average True Range over DefaultATRWindow days (0 if no history)
+	ATRPercent    float64 // ATR normalized by VWAP, 0 if VWAP is unusable
+	Recent5DayAvg float64 // Mean of the Average price over the most recent 5 days of history
 }
 
 // ScanContracts finds profitable public contracts by comparing contract price to market value.
@@ -188,6 +202,12 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 	var contractsMu sync.Mutex
 	var wg sync.WaitGroup
 
+	// Hedge-region buy books, fetched once up front since HedgeRegions is a
+	// fixed configuration rather than something derived per contract.
+	hedgeRegions := params.HedgeRegions
+	hedgeOrders := make(map[int32][]esi.MarketOrder, len(hedgeRegions)) // regionID -> orders
+	var hedgeOrdersMu sync.Mutex
+
 	progress(fmt.Sprintf("Fetching market orders + contracts from %d regions...", len(buyRegions)))
 
 	wg.Add(2)
@@ -202,6 +222,18 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 			buyOrdersForLiquidation = s.fetchOrders(sellRegions, "buy", sellSystems)
 		}()
 	}
+	if contractInstant && len(hedgeRegions) > 0 {
+		for _, regionID := range hedgeRegions {
+			wg.Add(1)
+			go func(regionID int32) {
+				defer wg.Done()
+				orders := s.fetchOrders(map[int32]bool{regionID: true}, "buy", nil)
+				hedgeOrdersMu.Lock()
+				hedgeOrders[regionID] = orders
+				hedgeOrdersMu.Unlock()
+			}(regionID)
+		}
+	}
 	go func() {
 		defer wg.Done()
 		// Fetch contracts from ALL regions in PARALLEL (with caching)
@@ -257,6 +289,29 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 		}
 	}
 
+	// Estimate-mode pricing input: sell-side book depth by type, walked by
+	// ComputeSellPlacementPlan to find where a relisted item would
+	// realistically need to sit rather than at the untouched top of book.
+	sellOrdersByType := make(map[int32][]esi.MarketOrder)
+	if !contractInstant {
+		for _, o := range sellOrders {
+			sellOrdersByType[o.TypeID] = append(sellOrdersByType[o.TypeID], o)
+		}
+	}
+
+	// Hedge-region buy-book depth by (regionID, typeID), for the multi-region
+	// greedy fill in the instant-liquidation branch below.
+	hedgeOrdersByTypeByRegion := make(map[int32]map[int32][]esi.MarketOrder, len(hedgeRegions))
+	if contractInstant {
+		for regionID, orders := range hedgeOrders {
+			byType := make(map[int32][]esi.MarketOrder)
+			for _, o := range orders {
+				byType[o.TypeID] = append(byType[o.TypeID], o)
+			}
+			hedgeOrdersByTypeByRegion[regionID] = byType
+		}
+	}
+
 	// Build price data map: typeID -> itemPriceData
 	// Track min price, total volume, and order count per type
 	priceData := make(map[int32]*itemPriceData)
@@ -365,6 +420,33 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 			continue
 		}
 
+		// Resolved before the item loop, and reused for the result's
+		// system/region below, because the hedge books need jumps from the
+		// contract's station to each hub region.
+		contractSysID := s.locationToSystem(contract.StartLocationID, marketLocationSystems)
+		var hedgeBooksByType map[int32][]regionHedgeBook
+		if contractInstant && len(hedgeRegions) > 0 {
+			hedgeBooksByType = make(map[int32][]regionHedgeBook)
+			for _, regionID := range hedgeRegions {
+				hubStationID, ok := hedgeHubStations[regionID]
+				if !ok {
+					continue
+				}
+				hubSysID := s.locationToSystem(hubStationID, marketLocationSystems)
+				jumps := 0
+				if contractSysID != 0 && hubSysID != 0 {
+					jumps = s.jumpsBetweenWithSecurity(contractSysID, hubSysID, params.MinRouteSecurity)
+				}
+				for typeID, orders := range hedgeOrdersByTypeByRegion[regionID] {
+					hedgeBooksByType[typeID] = append(hedgeBooksByType[typeID], regionHedgeBook{
+						regionID: regionID,
+						orders:   orders,
+						jumps:    jumps,
+					})
+				}
+			}
+		}
+
 		var marketValue float64
 		var itemCount int32
 		var pricedCount int        // how many item types we could price
@@ -372,9 +454,14 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 		var topItems []string      // for generating title
 		var lowVolumeItems int     // items with suspicious low trading volume
 		var highDeviationItems int // items where sell price deviates significantly from VWAP
+		var volatilityRejected int // items skipped for crashing >DriftRejectATRMultiple*ATR below VWAP
+		var atrPercentSum float64  // summed over pricedCount, for this contract's AvgATRPercent
 		fullLiquidationProb := 1.0
 		maxFillDays := 0.0
 		expectedGrossByFill := 0.0
+		var totalFreightCost float64
+		var hedgeBreakdown []RegionHedgeFill
+		var itemPlacements []ItemPlacement
 
 		hasBPO := false
 		for _, item := range items {
@@ -397,16 +484,33 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 
 			if contractInstant {
 				book := buyOrdersByType[item.TypeID]
-				if len(book) == 0 {
-					continue
-				}
-				plan := ComputeExecutionPlan(book, item.Quantity, false)
-				if !plan.CanFill || plan.ExpectedPrice <= 0 {
-					continue
+
+				var expectedPrice float64
+				if len(hedgeRegions) > 0 {
+					itemVolume := 0.0
+					if typeName, ok := s.SDE.Types[item.TypeID]; ok {
+						itemVolume = typeName.Volume
+					}
+					plan := computeHedgedExecutionPlan(book, hedgeBooksByType[item.TypeID], item.Quantity, itemVolume, params.SalesTaxPercent, params.FreightISKPerJumpPerM3)
+					if !plan.CanFill || plan.ExpectedPrice <= 0 {
+						continue
+					}
+					expectedPrice = plan.ExpectedPrice
+					totalFreightCost += plan.FreightCost
+					hedgeBreakdown = append(hedgeBreakdown, plan.Breakdown...)
+				} else {
+					if len(book) == 0 {
+						continue
+					}
+					plan := ComputeExecutionPlan(book, item.Quantity, false)
+					if !plan.CanFill || plan.ExpectedPrice <= 0 {
+						continue
+					}
+					expectedPrice = plan.ExpectedPrice
 				}
 
 				pricedCount++
-				marketValue += plan.ExpectedPrice * float64(item.Quantity)
+				marketValue += expectedPrice * float64(item.Quantity)
 				itemCount += item.Quantity
 
 				if typeName, ok := s.SDE.Types[item.TypeID]; ok {
@@ -419,9 +523,20 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 				continue
 			}
 
-				pd, ok := priceData[item.TypeID]
-				if !ok || pd.MinSellPrice == 0 || pd.MinSellPrice == math.MaxFloat64 {
-					continue // can't price this item
+			pd, ok := priceData[item.TypeID]
+			if !ok || pd.MinSellPrice == 0 || pd.MinSellPrice == math.MaxFloat64 {
+				continue // can't price this item
+			}
+
+			// Reject items whose recent 5-day mean price has fallen more
+			// than DriftRejectATRMultiple*ATR below their VWAP baseline:
+			// the item crashed after the contract was listed, so VWAP is a
+			// stale (overly optimistic) estimate of what it'll fetch now.
+			if pd.ATR > 0 && pd.Recent5DayAvg > 0 && pd.VWAP > 0 {
+				if drift := pd.VWAP - pd.Recent5DayAvg; drift > DriftRejectATRMultiple*pd.ATR {
+					volatilityRejected++
+					continue
+				}
 			}
 
 			// Determine the best price to use: prefer VWAP if available and reliable
@@ -446,30 +561,61 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 				usePrice = pd.MinSellPrice
 			}
 
+			// Dumping item.Quantity units onto the market pushes below the
+			// untouched top of book; walk the sell side to find where the
+			// listing would realistically need to sit, and use it if it's
+			// more conservative than the VWAP-based estimate above.
+			if plan := ComputeSellPlacementPlan(sellOrdersByType[item.TypeID], item.Quantity, ContractFillParticipation); plan.CanPrice {
+				itemPlacements = append(itemPlacements, ItemPlacement{
+					TypeID:         item.TypeID,
+					Quantity:       item.Quantity,
+					PlacementPrice: plan.PlacementPrice,
+				})
+				if plan.PlacementPrice > 0 && plan.PlacementPrice < usePrice {
+					usePrice = plan.PlacementPrice
+				}
+			}
+
 			// Track items with low daily volume (unreliable pricing)
 			if pd.DailyVolume < MinDailyVolumeForContract {
 				lowVolumeItems++
 			}
 
-				pricedCount++
-				marketValue += usePrice * float64(item.Quantity)
-				itemCount += item.Quantity
+			pricedCount++
+			marketValue += usePrice * float64(item.Quantity)
+			itemCount += item.Quantity
+			atrPercentSum += pd.ATRPercent
 
-				dailyVol := effectiveDailyVolume(pd)
-				fillDays := estimateFillDays(item.Quantity, dailyVol)
-				itemFillProb := fillProbabilityWithinDays(fillDays, float64(holdDays))
-				fullLiquidationProb *= itemFillProb
-				if math.IsInf(fillDays, 1) {
-					if maxFillDays < float64(holdDays)*10 {
-						maxFillDays = float64(holdDays) * 10
-					}
-				} else if fillDays > maxFillDays {
-					maxFillDays = fillDays
+			dailyVol := effectiveDailyVolume(pd)
+			fillDays := estimateFillDays(item.Quantity, dailyVol)
+			if s.FillPriors != nil {
+				fillDays = s.FillPriors.Shrink(item.TypeID, fillDays)
+			}
+			itemFillProb := fillProbabilityWithinDays(fillDays, float64(holdDays))
+			fullLiquidationProb *= itemFillProb
+			if math.IsInf(fillDays, 1) {
+				if maxFillDays < float64(holdDays)*10 {
+					maxFillDays = float64(holdDays) * 10
 				}
-				expectedGrossByFill += usePrice * float64(item.Quantity) * itemFillProb
+			} else if fillDays > maxFillDays {
+				maxFillDays = fillDays
+			}
 
-				// Build item name for title generation
-				if typeName, ok := s.SDE.Types[item.TypeID]; ok {
+			// Scale the conservative haircut up for volatile items instead
+			// of applying ContractConservativePriceHaircut flat across the
+			// whole contract: a high-ATR item gets marked down harder.
+			haircut := ContractConservativePriceHaircut
+			if scaled := DefaultATRHaircutFactor * pd.ATRPercent; scaled > haircut {
+				haircut = scaled
+			}
+			conservativePrice := usePrice * (1 - haircut)
+			if conservativePrice < 0 {
+				conservativePrice = 0
+			}
+			expectedGrossByFill += conservativePrice * float64(item.Quantity) * itemFillProb
+
+			// Build item name for title generation
+			if typeName, ok := s.SDE.Types[item.TypeID]; ok {
 				if item.Quantity > 1 {
 					topItems = append(topItems, fmt.Sprintf("%dx %s", item.Quantity, typeName.Name))
 				} else {
@@ -510,8 +656,10 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 			continue
 		}
 
-		// Calculate profit
-		effectiveValue := marketValue * sellValueMult
+		// Calculate profit. Freight (when hedging across regions) is a flat
+		// ISK cost, not a percentage of sale value, so it's deducted
+		// directly rather than folded into sellValueMult.
+		effectiveValue := marketValue*sellValueMult - totalFreightCost
 		profit := effectiveValue - contract.Price
 		if profit <= 0 {
 			continue
@@ -535,8 +683,10 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 				continue
 			}
 			estLiqDays = maxFillDays
-			conservativeGross := expectedGrossByFill * (1.0 - ContractConservativePriceHaircut)
-			conservativeValue = conservativeGross * sellValueMult
+			// expectedGrossByFill already carries each item's ATR-scaled
+			// conservative haircut (see the per-item loop above), so no
+			// further flat haircut is applied here.
+			conservativeValue = expectedGrossByFill * sellValueMult
 			carryCost = contract.Price * ContractDailyCarryRate * float64(holdDays)
 			expectedProfit = conservativeValue - contract.Price - carryCost
 			if expectedProfit <= 0 {
@@ -563,8 +713,9 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 
 		stationName := s.ESI.StationName(contract.StartLocationID)
 
-		// Resolve system and region for the contract location
-		sysID := s.locationToSystem(contract.StartLocationID, marketLocationSystems)
+		// Resolve system and region for the contract location (already
+		// looked up as contractSysID above, for hedge-region jump costing)
+		sysID := contractSysID
 		sysName := ""
 		regionName := ""
 		if sysID != 0 {
@@ -596,26 +747,36 @@ func (s *Scanner) ScanContracts(params ScanParams, progress func(string)) ([]Con
 			profitPerJump = profit / float64(jumps)
 		}
 
+		avgATRPercent := 0.0
+		if pricedCount > 0 {
+			avgATRPercent = atrPercentSum / float64(pricedCount)
+		}
+
 		results = append(results, ContractResult{
-			ContractID:    contract.ContractID,
-			Title:         title,
-			Price:         contract.Price,
-			MarketValue:   marketValue,
-			Profit:        sanitizeFloat(profit),
-			MarginPercent: sanitizeFloat(margin),
+			ContractID:            contract.ContractID,
+			Title:                 title,
+			Price:                 contract.Price,
+			MarketValue:           marketValue,
+			Profit:                sanitizeFloat(profit),
+			MarginPercent:         sanitizeFloat(margin),
 			ExpectedProfit:        sanitizeFloat(expectedProfit),
 			ExpectedMarginPercent: sanitizeFloat(expectedMargin),
 			SellConfidence:        sanitizeFloat(sellConfidencePct),
 			EstLiquidationDays:    sanitizeFloat(estLiqDays),
 			ConservativeValue:     sanitizeFloat(conservativeValue),
 			CarryCost:             sanitizeFloat(carryCost),
-			Volume:        contract.Volume,
-			StationName:   stationName,
-			SystemName:    sysName,
-			RegionName:    regionName,
-			ItemCount:     itemCount,
-			Jumps:         jumps,
-			ProfitPerJump: sanitizeFloat(profitPerJump),
+			Volume:                contract.Volume,
+			StationName:           stationName,
+			SystemName:            sysName,
+			RegionName:            regionName,
+			ItemCount:             itemCount,
+			Jumps:                 jumps,
+			ProfitPerJump:         sanitizeFloat(profitPerJump),
+			HedgeBreakdown:        hedgeBreakdown,
+			TotalFreightCost:      sanitizeFloat(totalFreightCost),
+			AvgATRPercent:         sanitizeFloat(avgATRPercent),
+			VolatilityRejected:    volatilityRejected,
+			ItemPlacements:        itemPlacements,
 		})
 	}
 
@@ -697,6 +858,12 @@ func (s *Scanner) fetchContractItemsHistory(typeIDs map[int32]bool, priceData ma
 			pdata.VWAP = CalcVWAP(entries, 30)
 			pdata.DailyVolume = avgDailyVolume(entries, 7)
 			pdata.HasHistory = true
+
+			pdata.ATR = ComputeATR(entries, DefaultATRWindow)
+			if pdata.VWAP > 0 {
+				pdata.ATRPercent = pdata.ATR / pdata.VWAP
+			}
+			pdata.Recent5DayAvg = recentMeanPrice(entries, 5)
 		}(typeID, pd)
 	}
 
@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"math"
+
+	"eve-flipper/internal/esi"
+)
+
+// FactorCount is the number of features in the factor vector used by
+// FactorModel: [margin_pct, PeriodROI, -DOS, BvSRatio, -OBDS, momentum_5d, reversal_1d].
+const FactorCount = 7
+
+// FactorWeights are the fitted ridge-regression coefficients for a region's
+// FactorModel, one weight per feature in the factor vector (see
+// FactorCount), plus the per-column mean/stddev the fit z-scored against -
+// EstimateAlpha needs these to normalize live candidates the same way,
+// rather than scoring them against a different (or no) normalization.
+type FactorWeights struct {
+	RegionID int32                `json:"region_id"`
+	Weights  []float64            `json:"weights"`
+	Means    [FactorCount]float64 `json:"means"`
+	Stdevs   [FactorCount]float64 `json:"stdevs"`
+	Lambda   float64              `json:"lambda"`
+	Samples  int                  `json:"samples"`
+}
+
+// FactorModel scores StationTrade candidates by combining their existing
+// indicators into a single ranked alpha estimate, analogous to a linear
+// regression alpha stack.
+type FactorModel struct {
+	weights FactorWeights
+}
+
+// NewFactorModel wraps a set of fitted weights for live scoring.
+func NewFactorModel(weights FactorWeights) *FactorModel {
+	return &FactorModel{weights: weights}
+}
+
+// featureVector builds the raw (pre-zscore) factor vector for one candidate.
+// momentum5d and reversal1d come from the type's recent market history.
+func featureVector(trade StationTrade, history []esi.HistoryEntry) [FactorCount]float64 {
+	momentum5d, reversal1d := momentumReversal(history)
+	return [FactorCount]float64{
+		trade.MarginPercent,
+		trade.PeriodROI,
+		-trade.DOS,
+		trade.BvSRatio,
+		-trade.OBDS,
+		momentum5d,
+		reversal1d,
+	}
+}
+
+// momentumReversal computes momentum_5d = log(P_t/P_{t-5}) and
+// reversal_1d = -log(P_t/P_{t-1}) from chronologically-sorted history.
+// Callers are expected to pass history already sorted oldest-to-newest.
+func momentumReversal(history []esi.HistoryEntry) (momentum5d, reversal1d float64) {
+	n := len(history)
+	if n == 0 {
+		return 0, 0
+	}
+	last := history[n-1].Average
+	if last <= 0 {
+		return 0, 0
+	}
+	if n >= 6 && history[n-6].Average > 0 {
+		momentum5d = math.Log(last / history[n-6].Average)
+	}
+	if n >= 2 && history[n-2].Average > 0 {
+		reversal1d = -math.Log(last / history[n-2].Average)
+	}
+	return momentum5d, reversal1d
+}
+
+// zscoreColumns standardizes each column of a feature matrix independently
+// (mean 0, stddev 1), returning the z-scored matrix alongside the per-column
+// mean/stddev so the same transform can be applied to live candidates.
+func zscoreColumns(rows [][FactorCount]float64) (z [][FactorCount]float64, means, stdevs [FactorCount]float64) {
+	n := len(rows)
+	z = make([][FactorCount]float64, n)
+	if n == 0 {
+		return z, means, stdevs
+	}
+
+	for c := 0; c < FactorCount; c++ {
+		col := make([]float64, n)
+		for i, r := range rows {
+			col[i] = r[c]
+		}
+		means[c] = mean(col)
+		stdevs[c] = math.Sqrt(variance(col))
+	}
+	for i, r := range rows {
+		for c := 0; c < FactorCount; c++ {
+			if stdevs[c] > 0 {
+				z[i][c] = (r[c] - means[c]) / stdevs[c]
+			}
+		}
+	}
+	return z, means, stdevs
+}
+
+// FitFactorWeights fits ridge-regression weights w = (XᵀX + λI)⁻¹Xᵀy against
+// realized profit y, using the z-scored feature matrix X built from
+// historical station_results rows (features) and their realized profit
+// (targets). lambda is the ridge regularization strength.
+func FitFactorWeights(regionID int32, features [][FactorCount]float64, targets []float64, lambda float64) FactorWeights {
+	out := FactorWeights{RegionID: regionID, Lambda: lambda, Samples: len(features)}
+	if len(features) == 0 || len(features) != len(targets) {
+		return out
+	}
+
+	z, means, stdevs := zscoreColumns(features)
+	out.Means = means
+	out.Stdevs = stdevs
+
+	// XtX (FactorCount x FactorCount) + lambda*I
+	var xtx [FactorCount][FactorCount]float64
+	var xty [FactorCount]float64
+	for i, row := range z {
+		for a := 0; a < FactorCount; a++ {
+			xty[a] += row[a] * targets[i]
+			for b := 0; b < FactorCount; b++ {
+				xtx[a][b] += row[a] * row[b]
+			}
+		}
+	}
+	for a := 0; a < FactorCount; a++ {
+		xtx[a][a] += lambda
+	}
+
+	w := solveLinearSystem(xtx, xty)
+	out.Weights = w[:]
+	return out
+}
+
+// solveLinearSystem solves Ax = b via Gaussian elimination with partial
+// pivoting. Returns the zero vector if A is singular.
+func solveLinearSystem(a [FactorCount][FactorCount]float64, b [FactorCount]float64) [FactorCount]float64 {
+	const n = FactorCount
+	var m [n][n + 1]float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m[i][j] = a[i][j]
+		}
+		m[i][n] = b[i]
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		best := math.Abs(m[col][col])
+		for r := col + 1; r < n; r++ {
+			if v := math.Abs(m[r][col]); v > best {
+				pivot, best = r, v
+			}
+		}
+		if best < 1e-12 {
+			var zero [n]float64
+			return zero
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := m[r][col] / m[col][col]
+			for c := col; c <= n; c++ {
+				m[r][c] -= factor * m[col][c]
+			}
+		}
+	}
+
+	var x [n]float64
+	for i := 0; i < n; i++ {
+		if m[i][i] != 0 {
+			x[i] = m[i][n] / m[i][i]
+		}
+	}
+	return x
+}
+
+// EstimateAlpha scores a single live StationTrade candidate against the
+// fitted factor weights, returning an expected-profit-proportional score
+// callers can sort by instead of raw margin. The raw feature vector is
+// z-scored against the fit's own persisted means/stdevs first, so this
+// candidate is compared on the same scale the weights were fitted against.
+func (fm *FactorModel) EstimateAlpha(trade StationTrade, history []esi.HistoryEntry) float64 {
+	if fm == nil || len(fm.weights.Weights) != FactorCount {
+		return trade.MarginPercent
+	}
+	f := featureVector(trade, history)
+	var score float64
+	for i, w := range fm.weights.Weights {
+		zi := f[i]
+		if fm.weights.Stdevs[i] > 0 {
+			zi = (f[i] - fm.weights.Means[i]) / fm.weights.Stdevs[i]
+		} else {
+			zi = 0
+		}
+		score += zi * w
+	}
+	return score
+}
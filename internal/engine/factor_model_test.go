@@ -0,0 +1,54 @@
+package engine
+
+import "testing"
+
+func TestFitFactorWeights_RecoversLinearRelationship(t *testing.T) {
+	// target = 2 * margin_pct, all other features held at zero variance noise.
+	var features [][FactorCount]float64
+	var targets []float64
+	for i := 0; i < 20; i++ {
+		margin := float64(i)
+		var f [FactorCount]float64
+		f[0] = margin
+		features = append(features, f)
+		targets = append(targets, 2*margin)
+	}
+
+	weights := FitFactorWeights(10000002, features, targets, 0.01)
+	if weights.Samples != 20 {
+		t.Fatalf("Samples = %d, want 20", weights.Samples)
+	}
+	if len(weights.Weights) != FactorCount {
+		t.Fatalf("len(Weights) = %d, want %d", len(weights.Weights), FactorCount)
+	}
+	if weights.Weights[0] <= 0 {
+		t.Errorf("weight[0] = %v, want positive (margin is the driving factor)", weights.Weights[0])
+	}
+}
+
+func TestEstimateAlpha_FallsBackToMarginWithoutWeights(t *testing.T) {
+	fm := NewFactorModel(FactorWeights{})
+	trade := StationTrade{MarginPercent: 12.5}
+	if got := fm.EstimateAlpha(trade, nil); got != 12.5 {
+		t.Errorf("EstimateAlpha fallback = %v, want 12.5", got)
+	}
+}
+
+func TestEstimateAlpha_UsesFitMeanStdevNotRawFeatures(t *testing.T) {
+	// margin_pct is centered at 10 with stdev 2 at fit time; a live
+	// candidate at margin=12 should score as z=1, not as the raw value 12.
+	weights := FactorWeights{
+		Weights: make([]float64, FactorCount),
+		Means:   [FactorCount]float64{10},
+		Stdevs:  [FactorCount]float64{2},
+	}
+	weights.Weights[0] = 5
+
+	fm := NewFactorModel(weights)
+	trade := StationTrade{MarginPercent: 12}
+	got := fm.EstimateAlpha(trade, nil)
+	want := 5.0 // z = (12-10)/2 = 1, score = 1*5
+	if got != want {
+		t.Errorf("EstimateAlpha = %v, want %v (should z-score against the fit's own means/stdevs)", got, want)
+	}
+}
@@ -38,6 +38,11 @@ type ImpactEstimate struct {
 	// OptimalSlices: suggested number of slices for TWAP execution.
 	// Based on participation rate: each slice ≤ targetPct of daily volume.
 	OptimalSlices int `json:"optimal_slices"`
+	// Schedule: per-slice quantities from the Almgren-Chriss optimal execution
+	// trajectory, populated by EstimateImpactWithSchedule when called with a
+	// positive risk aversion. Nothing in this checkout calls it yet - nil
+	// means the caller should fall back to uniform TWAP over OptimalSlices.
+	Schedule []float64 `json:"schedule,omitempty"`
 	// Params used for this estimate.
 	Params ImpactParams `json:"params"`
 }
@@ -175,6 +180,157 @@ func OptimalSlicesVolume(quantity float64, avgDailyVolume float64, targetPct flo
 	return int(n)
 }
 
+const (
+	// DefaultPermanentImpactFraction is γ as a fraction of η when the caller
+	// doesn't supply a calibrated permanent-impact coefficient.
+	DefaultPermanentImpactFraction = 0.1
+)
+
+// temporaryImpactCoefficient derives η (temporary impact) from the calibrated
+// Amihud/sqrt-law fits: η is the price impact (in ISK-equivalent fractional
+// terms) per unit of trading rate implied by the square-root law at the
+// average daily volume.
+func temporaryImpactCoefficient(params ImpactParams) float64 {
+	if params.AvgDailyVolume <= 0 || params.Sigma <= 0 {
+		return 0
+	}
+	return params.Sigma / math.Sqrt(params.AvgDailyVolume)
+}
+
+// AlmgrenChrissSchedule computes the optimal liquidation trajectory for
+// `quantity` units over `horizonSlices` slices using the Almgren-Chriss
+// framework: given volatility σ, temporary impact η, permanent impact γ,
+// and risk aversion λ, holdings at step k are
+//
+//	x_k = X · sinh(κ(T−kτ)) / sinh(κT)
+//
+// with κ = sqrt(λσ²/η) and τ = T/N (T is normalized to 1, i.e. one trading
+// day). The trade in slice k is n_k = x_{k-1} - x_k. As λ→0, κ→0 and the
+// trajectory degenerates to uniform TWAP (x_k linear in k).
+//
+// Returns the per-slice trade quantities (length horizonSlices, summing to
+// quantity).
+func AlmgrenChrissSchedule(params ImpactParams, quantity float64, horizonSlices int, riskAversion float64) []float64 {
+	if quantity <= 0 || horizonSlices <= 0 {
+		return nil
+	}
+	if horizonSlices == 1 {
+		return []float64{quantity}
+	}
+
+	n := horizonSlices
+	trades := make([]float64, n)
+
+	eta := temporaryImpactCoefficient(params)
+	sigmaSq := params.SigmaSq
+	if sigmaSq <= 0 && params.Sigma > 0 {
+		sigmaSq = params.Sigma * params.Sigma
+	}
+
+	if riskAversion <= 0 || eta <= 0 || sigmaSq <= 0 {
+		// Risk-neutral limit: uniform TWAP, one slice per tau.
+		per := quantity / float64(n)
+		for k := range trades {
+			trades[k] = per
+		}
+		return trades
+	}
+
+	const T = 1.0 // horizon normalized to one trading day
+	tau := T / float64(n)
+	kappaSq := riskAversion * sigmaSq / eta
+	kappa := math.Sqrt(kappaSq)
+
+	denom := math.Sinh(kappa * T)
+	if denom == 0 || math.IsNaN(denom) || math.IsInf(denom, 0) {
+		per := quantity / float64(n)
+		for k := range trades {
+			trades[k] = per
+		}
+		return trades
+	}
+
+	holdings := func(k int) float64 {
+		t := float64(k) * tau
+		return quantity * math.Sinh(kappa*(T-t)) / denom
+	}
+
+	prev := quantity
+	for k := 1; k <= n; k++ {
+		var xk float64
+		if k == n {
+			xk = 0
+		} else {
+			xk = holdings(k)
+		}
+		trades[k-1] = prev - xk
+		prev = xk
+	}
+	return trades
+}
+
+// AlmgrenChrissCost returns the expected cost E[C] and variance V[C] of the
+// given execution schedule, per the Almgren-Chriss closed forms:
+//
+//	E[C] = ½γX² + ε|N| + η̃·Σn_k²
+//	V[C] = σ²·Σ(x_k²·τ)
+//
+// where ε is a fixed per-slice cost (spread/fees, caller-supplied), γ
+// defaults to DefaultPermanentImpactFraction·η when not calibrated separately,
+// and η̃ = η − ½γτ with τ = 1/N.
+func AlmgrenChrissCost(params ImpactParams, schedule []float64, epsilonPerSlice float64) (expectedCost, variance float64) {
+	n := len(schedule)
+	if n == 0 {
+		return 0, 0
+	}
+
+	var quantity float64
+	for _, nk := range schedule {
+		quantity += nk
+	}
+
+	eta := temporaryImpactCoefficient(params)
+	gamma := eta * DefaultPermanentImpactFraction
+	tau := 1.0 / float64(n)
+	etaTilde := eta - 0.5*gamma*tau
+
+	expectedCost = 0.5*gamma*quantity*quantity + epsilonPerSlice*float64(n)
+	for _, nk := range schedule {
+		expectedCost += etaTilde * nk * nk
+	}
+
+	sigmaSq := params.SigmaSq
+	if sigmaSq <= 0 && params.Sigma > 0 {
+		sigmaSq = params.Sigma * params.Sigma
+	}
+	if sigmaSq > 0 {
+		remaining := quantity
+		for _, nk := range schedule {
+			remaining -= nk
+			variance += remaining * remaining * tau
+		}
+		variance *= sigmaSq
+	}
+
+	return expectedCost, variance
+}
+
+// EstimateImpactWithSchedule is EstimateImpact plus an optional Almgren-Chriss
+// execution schedule, populated when riskAversion > 0 (i.e. the caller wants
+// risk-aware slicing rather than plain TWAP). Nothing in this checkout calls
+// this yet; wiring it to a per-request risk aversion is left to the caller.
+func EstimateImpactWithSchedule(params ImpactParams, quantity float64, refPrice float64, horizonSlices int, riskAversion float64) ImpactEstimate {
+	out := EstimateImpact(params, quantity, refPrice)
+	if riskAversion > 0 {
+		slices := horizonSlices
+		if slices <= 0 {
+			slices = out.OptimalSlices
+		}
+		out.Schedule = AlmgrenChrissSchedule(params, quantity, slices, riskAversion)
+	}
+	return out
+}
+
 // EstimateImpact returns impact estimate for a given quantity using calibrated params.
 // refPrice is a reference price (e.g. current best price) to convert % impact to ISK.
 func EstimateImpact(params ImpactParams, quantity float64, refPrice float64) ImpactEstimate {
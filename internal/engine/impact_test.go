@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAlmgrenChrissSchedule_DegeneratesToTWAPWhenRiskNeutral(t *testing.T) {
+	params := ImpactParams{Sigma: 0.02, SigmaSq: 0.0004, AvgDailyVolume: 100_000, Valid: true}
+
+	// riskAversion <= 0 is the explicit risk-neutral limit.
+	schedule := AlmgrenChrissSchedule(params, 1000, 4, 0)
+	for i, got := range schedule {
+		if want := 250.0; got != want {
+			t.Errorf("schedule[%d] = %v, want %v (uniform TWAP)", i, got, want)
+		}
+	}
+}
+
+func TestAlmgrenChrissSchedule_SumsToQuantityAndFrontLoadsWithRiskAversion(t *testing.T) {
+	params := ImpactParams{Sigma: 0.05, SigmaSq: 0.0025, AvgDailyVolume: 50_000, Valid: true}
+
+	schedule := AlmgrenChrissSchedule(params, 1000, 5, 10)
+	if len(schedule) != 5 {
+		t.Fatalf("len(schedule) = %d, want 5", len(schedule))
+	}
+
+	var sum float64
+	for i, nk := range schedule {
+		if nk <= 0 {
+			t.Errorf("schedule[%d] = %v, want > 0", i, nk)
+		}
+		sum += nk
+	}
+	if math.Abs(sum-1000) > 1e-6 {
+		t.Errorf("sum(schedule) = %v, want 1000 (must fully liquidate)", sum)
+	}
+
+	// A risk-averse trader front-loads: each slice should be no larger than
+	// the one before it.
+	for i := 1; i < len(schedule); i++ {
+		if schedule[i] > schedule[i-1]+1e-9 {
+			t.Errorf("schedule[%d] = %v > schedule[%d] = %v, want a non-increasing (front-loaded) trajectory", i, schedule[i], i-1, schedule[i-1])
+		}
+	}
+}
+
+func TestAlmgrenChrissSchedule_InvalidInputs(t *testing.T) {
+	params := ImpactParams{Sigma: 0.02, SigmaSq: 0.0004, AvgDailyVolume: 100_000, Valid: true}
+
+	if got := AlmgrenChrissSchedule(params, 0, 4, 1); got != nil {
+		t.Errorf("AlmgrenChrissSchedule(quantity=0) = %v, want nil", got)
+	}
+	if got := AlmgrenChrissSchedule(params, 1000, 0, 1); got != nil {
+		t.Errorf("AlmgrenChrissSchedule(horizonSlices=0) = %v, want nil", got)
+	}
+	if got := AlmgrenChrissSchedule(params, 1000, 1, 1); len(got) != 1 || got[0] != 1000 {
+		t.Errorf("AlmgrenChrissSchedule(horizonSlices=1) = %v, want [1000]", got)
+	}
+}
+
+func TestAlmgrenChrissCost_ZeroVarianceWhenNoVolatility(t *testing.T) {
+	params := ImpactParams{AvgDailyVolume: 100_000} // no Sigma/SigmaSq calibrated
+	_, variance := AlmgrenChrissCost(params, []float64{250, 250, 250, 250}, 0)
+	if variance != 0 {
+		t.Errorf("variance = %v, want 0 with no calibrated volatility", variance)
+	}
+}
+
+func TestAlmgrenChrissCost_FrontLoadedScheduleCostsMoreButRisksLessThanUniformTWAP(t *testing.T) {
+	params := ImpactParams{Sigma: 0.05, SigmaSq: 0.0025, AvgDailyVolume: 50_000, Valid: true}
+
+	uniform := []float64{250, 250, 250, 250}
+	frontLoaded, _ := AlmgrenChrissCost(params, []float64{700, 200, 70, 30}, 0)
+	uniformCost, _ := AlmgrenChrissCost(params, uniform, 0)
+
+	// Sum-of-squares is minimized by the uniform split, so a front-loaded
+	// schedule should cost strictly more in expectation...
+	if frontLoaded <= uniformCost {
+		t.Errorf("frontLoaded expected cost = %v, want > uniform expected cost %v", frontLoaded, uniformCost)
+	}
+
+	// ...but should carry less price risk (variance), since exposure is
+	// unwound faster.
+	_, frontLoadedVariance := AlmgrenChrissCost(params, []float64{700, 200, 70, 30}, 0)
+	_, uniformVariance := AlmgrenChrissCost(params, uniform, 0)
+	if frontLoadedVariance >= uniformVariance {
+		t.Errorf("frontLoaded variance = %v, want < uniform variance %v", frontLoadedVariance, uniformVariance)
+	}
+}
+
+func TestAlmgrenChrissCost_EmptySchedule(t *testing.T) {
+	cost, variance := AlmgrenChrissCost(ImpactParams{}, nil, 0)
+	if cost != 0 || variance != 0 {
+		t.Errorf("AlmgrenChrissCost(nil) = (%v, %v), want (0, 0)", cost, variance)
+	}
+}
@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// InventoryItem is one item type within a purchased contract, tracked from
+// its buy-time snapshot through to liquidation.
+type InventoryItem struct {
+	TypeID       int32
+	TypeName     string
+	Quantity     int32   // quantity bought
+	Remaining    int32   // quantity not yet sold, decremented by RecordSale
+	BuyTimePrice float64 // per-unit sell-side price this item was valued at when the contract was bought
+}
+
+// InventoryPosition is a contract the user has purchased after a scan,
+// tracked from buy time through liquidation: its priced item set at
+// purchase, plus realized-vs-predicted profit and liquidation speed as
+// subsequent scans refresh market state against it.
+type InventoryPosition struct {
+	ContractID int32
+	RegionID   int32
+	BoughtAt   time.Time
+	Cost       float64 // contract price paid
+	Items      []InventoryItem
+
+	PredictedExpectedProfit     float64
+	PredictedEstLiquidationDays float64
+
+	RealizedProfit   float64 // ISK banked from items explicitly recorded sold via RecordSale
+	UnrealizedProfit float64 // mark-to-market value of remaining items, minus carry cost to date
+	CarryCostToDate  float64
+	ActualHeldDays   float64 // days since BoughtAt, refreshed by UpdateInventory
+	Closed           bool    // true once every item has sold
+}
+
+// InventoryTracker keeps the in-memory set of purchased contracts being
+// watched for liquidation progress, keyed by contract ID. Unlike
+// TrailingTracker, this is in-memory only for now: internal/db's
+// AddInventoryPosition/UpdateInventoryPositionState/CloseInventoryPosition/
+// ListOpenInventoryPositions exist for a future restart-resume path but
+// nothing calls them yet, so a process restart currently loses all tracked
+// purchase history.
+type InventoryTracker struct {
+	mu        sync.Mutex
+	positions map[int32]*InventoryPosition
+}
+
+// NewInventoryTracker creates an empty tracker.
+func NewInventoryTracker() *InventoryTracker {
+	return &InventoryTracker{positions: make(map[int32]*InventoryPosition)}
+}
+
+// Track begins watching a purchased contract.
+func (t *InventoryTracker) Track(p *InventoryPosition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.positions[p.ContractID] = p
+}
+
+// Untrack stops watching a contract (e.g. once the operator archives it).
+func (t *InventoryTracker) Untrack(contractID int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.positions, contractID)
+}
+
+// Get returns the tracked position for contractID, if any.
+func (t *InventoryTracker) Get(contractID int32) (*InventoryPosition, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.positions[contractID]
+	return p, ok
+}
+
+// List returns every currently-tracked position, open and closed.
+func (t *InventoryTracker) List() []*InventoryPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]*InventoryPosition, 0, len(t.positions))
+	for _, p := range t.positions {
+		out = append(out, p)
+	}
+	return out
+}
+
+// fillRateStat is the running observed fill-time mean for one item type.
+type fillRateStat struct {
+	count    int
+	meanDays float64
+}
+
+// DefaultFillPriorStrength (k) is how many "virtual observations" a raw
+// estimateFillDays estimate counts as when blended against a type's
+// observed fill history: with few closed positions the shrinkage is
+// light, and it strengthens as more positions close for that type.
+const DefaultFillPriorStrength = 3.0
+
+// FillRatePriors accumulates observed actual liquidation days per item
+// type across closed inventory positions, and shrinks a raw
+// estimateFillDays estimate toward that observed history - the scanner's
+// confidence model improves over time instead of always trusting the
+// static ContractFillParticipation-derived estimate.
+type FillRatePriors struct {
+	mu    sync.Mutex
+	stats map[int32]*fillRateStat
+}
+
+// NewFillRatePriors creates an empty prior store.
+func NewFillRatePriors() *FillRatePriors {
+	return &FillRatePriors{stats: make(map[int32]*fillRateStat)}
+}
+
+// Observe records one item type's actual time-to-fully-liquidate,
+// updating its running mean.
+func (f *FillRatePriors) Observe(typeID int32, actualFillDays float64) {
+	if actualFillDays < 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.stats[typeID]
+	if !ok {
+		s = &fillRateStat{}
+		f.stats[typeID] = s
+	}
+	s.meanDays = (s.meanDays*float64(s.count) + actualFillDays) / float64(s.count+1)
+	s.count++
+}
+
+// Shrink blends a raw estimateFillDays estimate toward this type's
+// observed mean fill time, weighted by how many observations back the
+// prior (Bayesian shrinkage). With no observations yet it returns
+// rawEstimate unchanged.
+func (f *FillRatePriors) Shrink(typeID int32, rawEstimate float64) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.stats[typeID]
+	if !ok || s.count == 0 {
+		return rawEstimate
+	}
+	n := float64(s.count)
+	return (n*s.meanDays + DefaultFillPriorStrength*rawEstimate) / (n + DefaultFillPriorStrength)
+}
+
+// TrackPurchase begins tracking a just-bought contract: fetches its items
+// and snapshots per-type buy-time pricing from regionID's current sell
+// orders, then starts watching it for liquidation progress via
+// UpdateInventory.
+func (s *Scanner) TrackPurchase(contractID int32, regionID int32) error {
+	if s.Inventory == nil {
+		s.Inventory = NewInventoryTracker()
+	}
+
+	contracts, err := s.ESI.FetchRegionContractsCached(s.ContractsCache, regionID)
+	if err != nil {
+		return err
+	}
+	var bought *esi.PublicContract
+	for i := range contracts {
+		if contracts[i].ContractID == contractID {
+			bought = &contracts[i]
+			break
+		}
+	}
+	if bought == nil {
+		return fmt.Errorf("contract %d not found in region %d", contractID, regionID)
+	}
+
+	itemsByContract := s.ESI.FetchContractItemsBatch([]int32{contractID}, s.ContractItemsCache, func(done, total int) {})
+	lines := aggregateItemLines(itemsByContract[contractID])
+
+	sellOrders := s.fetchOrders(map[int32]bool{regionID: true}, "sell", nil)
+	byType := make(map[int32][]esi.MarketOrder)
+	for _, o := range sellOrders {
+		byType[o.TypeID] = append(byType[o.TypeID], o)
+	}
+
+	items := make([]InventoryItem, 0, len(lines))
+	var totalValue float64
+	for _, line := range lines {
+		buyPrice := 0.0
+		if plan := ComputeSellPlacementPlan(byType[line.TypeID], line.Quantity, ContractFillParticipation); plan.CanPrice {
+			buyPrice = plan.PlacementPrice
+		}
+		name := ""
+		if typeName, ok := s.SDE.Types[line.TypeID]; ok {
+			name = typeName.Name
+		}
+		items = append(items, InventoryItem{
+			TypeID:       line.TypeID,
+			TypeName:     name,
+			Quantity:     line.Quantity,
+			Remaining:    line.Quantity,
+			BuyTimePrice: buyPrice,
+		})
+		totalValue += buyPrice * float64(line.Quantity)
+	}
+
+	s.Inventory.Track(&InventoryPosition{
+		ContractID:                  contractID,
+		RegionID:                    regionID,
+		BoughtAt:                    time.Now().UTC(),
+		Cost:                        bought.Price,
+		Items:                       items,
+		PredictedExpectedProfit:     totalValue - bought.Price,
+		PredictedEstLiquidationDays: float64(DefaultContractHoldDays),
+	})
+	return nil
+}
+
+// UpdateInventory refreshes market state for every open tracked position:
+// re-prices remaining quantity per type against current sell-side book
+// depth, accrues carry cost via ContractDailyCarryRate, and closes (and
+// feeds FillPriors for) any position whose items have all been recorded
+// sold.
+func (s *Scanner) UpdateInventory() error {
+	if s.Inventory == nil {
+		return nil
+	}
+
+	for _, pos := range s.Inventory.List() {
+		if pos.Closed {
+			continue
+		}
+
+		sellOrders := s.fetchOrders(map[int32]bool{pos.RegionID: true}, "sell", nil)
+		byType := make(map[int32][]esi.MarketOrder)
+		for _, o := range sellOrders {
+			byType[o.TypeID] = append(byType[o.TypeID], o)
+		}
+
+		var unrealized float64
+		allSold := true
+		for i := range pos.Items {
+			item := &pos.Items[i]
+			if item.Remaining <= 0 {
+				continue
+			}
+			allSold = false
+			if plan := ComputeSellPlacementPlan(byType[item.TypeID], item.Remaining, ContractFillParticipation); plan.CanPrice {
+				unrealized += plan.PlacementPrice * float64(item.Remaining)
+			}
+		}
+
+		pos.ActualHeldDays = time.Since(pos.BoughtAt).Hours() / 24
+		pos.CarryCostToDate = pos.Cost * ContractDailyCarryRate * pos.ActualHeldDays
+		pos.UnrealizedProfit = unrealized - pos.CarryCostToDate
+
+		if allSold {
+			pos.Closed = true
+			if s.FillPriors != nil {
+				for _, item := range pos.Items {
+					s.FillPriors.Observe(item.TypeID, pos.ActualHeldDays)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RecordSale records quantity units of a tracked position's item type as
+// actually sold for proceeds ISK (e.g. once the user confirms a market or
+// contract fill), crediting RealizedProfit and decrementing Remaining so
+// InventoryReport reflects real progress rather than a re-estimate. There
+// is no live order-fill feed to observe this automatically, so callers are
+// expected to report sales as they happen.
+func (s *Scanner) RecordSale(contractID, typeID int32, quantity int32, proceeds float64) error {
+	if s.Inventory == nil {
+		return fmt.Errorf("no purchases tracked")
+	}
+	pos, ok := s.Inventory.Get(contractID)
+	if !ok {
+		return fmt.Errorf("contract %d not tracked", contractID)
+	}
+	for i := range pos.Items {
+		item := &pos.Items[i]
+		if item.TypeID != typeID {
+			continue
+		}
+		if quantity > item.Remaining {
+			quantity = item.Remaining
+		}
+		item.Remaining -= quantity
+		pos.RealizedProfit += proceeds
+		return nil
+	}
+	return fmt.Errorf("type %d not found in contract %d", typeID, contractID)
+}
+
+// InventoryReport returns a snapshot of every tracked position (open and
+// closed), freshest as of the last UpdateInventory call.
+func (s *Scanner) InventoryReport() []*InventoryPosition {
+	if s.Inventory == nil {
+		return nil
+	}
+	return s.Inventory.List()
+}
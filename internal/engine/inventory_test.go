@@ -0,0 +1,57 @@
+package engine
+
+import "testing"
+
+func TestInventoryTracker_TrackUntrackList(t *testing.T) {
+	tr := NewInventoryTracker()
+	tr.Track(&InventoryPosition{ContractID: 1})
+	tr.Track(&InventoryPosition{ContractID: 2})
+
+	if got := len(tr.List()); got != 2 {
+		t.Fatalf("List() length = %d, want 2", got)
+	}
+	if _, ok := tr.Get(1); !ok {
+		t.Fatalf("expected Get(1) to find a tracked position")
+	}
+
+	tr.Untrack(1)
+	if _, ok := tr.Get(1); ok {
+		t.Fatalf("expected Get(1) to miss after Untrack")
+	}
+	if got := len(tr.List()); got != 1 {
+		t.Fatalf("List() length after Untrack = %d, want 1", got)
+	}
+}
+
+func TestFillRatePriors_ShrinkWithNoObservationsReturnsRaw(t *testing.T) {
+	p := NewFillRatePriors()
+	if got := p.Shrink(34, 5.0); got != 5.0 {
+		t.Fatalf("Shrink with no observations = %v, want 5.0 unchanged", got)
+	}
+}
+
+func TestFillRatePriors_ShrinkBlendsTowardObservedMean(t *testing.T) {
+	p := NewFillRatePriors()
+	p.Observe(34, 10.0)
+	p.Observe(34, 10.0)
+
+	// n=2 observations at mean 10, raw estimate of 2: shrunk result should
+	// land strictly between the two, closer to the raw estimate since
+	// DefaultFillPriorStrength (3) outweighs n (2).
+	got := p.Shrink(34, 2.0)
+	want := (2*10.0 + DefaultFillPriorStrength*2.0) / (2 + DefaultFillPriorStrength)
+	if got != want {
+		t.Fatalf("Shrink = %v, want %v", got, want)
+	}
+	if got <= 2.0 || got >= 10.0 {
+		t.Fatalf("Shrink = %v, want strictly between raw estimate 2.0 and observed mean 10.0", got)
+	}
+}
+
+func TestFillRatePriors_ObserveIgnoresNegativeDays(t *testing.T) {
+	p := NewFillRatePriors()
+	p.Observe(34, -1)
+	if got := p.Shrink(34, 5.0); got != 5.0 {
+		t.Fatalf("Shrink after ignored negative observation = %v, want 5.0 unchanged", got)
+	}
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// DefaultPivotLength is how many trailing daily candles PivotDetector scans
+// for a confirmed bottom when the caller doesn't override it.
+const DefaultPivotLength = 120
+
+// DefaultPivotNeighbors is the default ±k window (in days) a bar's low must
+// beat to count as a pivot low.
+const DefaultPivotNeighbors = 5
+
+// PivotResult describes the most recent confirmed pivot low found in a
+// history window, for annotating FlipResult/StationTrade candidates.
+type PivotResult struct {
+	Confirmed      bool
+	BarsSincePivot int
+	PivotPrice     float64
+}
+
+// PivotDetector scans daily ESI market history for confirmed pivot lows,
+// adapting the pivotshort idea from bbgo to a long-side "wait for a
+// confirmed bottom" entry signal: a bar's low is a pivot low when it is the
+// strict minimum over its ±Neighbors window.
+type PivotDetector struct {
+	// Length is how many trailing days to consider. Defaults to
+	// DefaultPivotLength if <= 0.
+	Length int
+	// Neighbors is the ±k window a bar's low must strictly beat. Defaults to
+	// DefaultPivotNeighbors if <= 0.
+	Neighbors int
+}
+
+// NewPivotDetector builds a PivotDetector with the given length/neighbor
+// window, falling back to the package defaults for non-positive values.
+func NewPivotDetector(length, neighbors int) *PivotDetector {
+	if length <= 0 {
+		length = DefaultPivotLength
+	}
+	if neighbors <= 0 {
+		neighbors = DefaultPivotNeighbors
+	}
+	return &PivotDetector{Length: length, Neighbors: neighbors}
+}
+
+// Detect finds the most recent confirmed pivot low in entries and reports
+// how many bars have elapsed since it printed. Entries need not be
+// pre-sorted; Detect sorts a copy by date.
+func (p *PivotDetector) Detect(entries []esi.HistoryEntry) PivotResult {
+	k := p.Neighbors
+	if k <= 0 {
+		k = DefaultPivotNeighbors
+	}
+	length := p.Length
+	if length <= 0 {
+		length = DefaultPivotLength
+	}
+
+	if len(entries) < 2*k+1 {
+		return PivotResult{}
+	}
+
+	sorted := make([]esi.HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	start := len(sorted) - length
+	if start < k {
+		start = k
+	}
+
+	// Scan from most recent back to oldest so the first pivot found is the
+	// most recent one; a pivot needs k confirming bars on both sides so the
+	// newest possible pivot is k bars before the end.
+	for i := len(sorted) - 1 - k; i >= start; i-- {
+		if isPivotLow(sorted, i, k) {
+			return PivotResult{
+				Confirmed:      true,
+				BarsSincePivot: len(sorted) - 1 - i,
+				PivotPrice:     sorted[i].Lowest,
+			}
+		}
+	}
+	return PivotResult{}
+}
+
+func isPivotLow(entries []esi.HistoryEntry, i, k int) bool {
+	low := entries[i].Lowest
+	for j := i - k; j <= i+k; j++ {
+		if j == i {
+			continue
+		}
+		if entries[j].Lowest <= low {
+			return false
+		}
+	}
+	return true
+}
+
+// PassesPivotFilter reports whether a candidate's current buy price is
+// within maxAboveBuyPct percent of its most recent confirmed pivot low.
+// Candidates with no confirmed pivot never pass the filter.
+func PassesPivotFilter(pivot PivotResult, buyPrice, maxAboveBuyPct float64) bool {
+	if !pivot.Confirmed || pivot.PivotPrice <= 0 {
+		return false
+	}
+	aboveBy := (buyPrice - pivot.PivotPrice) / pivot.PivotPrice * 100
+	return aboveBy <= maxAboveBuyPct
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func dayEntries(lows []float64) []esi.HistoryEntry {
+	entries := make([]esi.HistoryEntry, len(lows))
+	for i, low := range lows {
+		entries[i] = esi.HistoryEntry{
+			Date:    string(rune('A' + i)), // monotonic for sort stability in test
+			Lowest:  low,
+			Highest: low + 1,
+			Average: low + 0.5,
+		}
+	}
+	return entries
+}
+
+func TestPivotDetector_FindsConfirmedBottom(t *testing.T) {
+	// A clear V-shape: low dips at index 5, confirmed by 2 bars on each side.
+	lows := []float64{10, 9, 8, 7, 6, 3, 6, 7, 8, 9}
+	det := NewPivotDetector(120, 2)
+	res := det.Detect(dayEntries(lows))
+	if !res.Confirmed {
+		t.Fatal("expected a confirmed pivot low")
+	}
+	if res.PivotPrice != 3 {
+		t.Errorf("PivotPrice = %v, want 3", res.PivotPrice)
+	}
+	if res.BarsSincePivot != len(lows)-1-5 {
+		t.Errorf("BarsSincePivot = %d, want %d", res.BarsSincePivot, len(lows)-1-5)
+	}
+}
+
+func TestPivotDetector_NoPivotOnMonotonicDecline(t *testing.T) {
+	lows := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	det := NewPivotDetector(120, 2)
+	res := det.Detect(dayEntries(lows))
+	if res.Confirmed {
+		t.Fatalf("expected no confirmed pivot on a monotonic decline, got %+v", res)
+	}
+}
+
+func TestPivotDetector_InsufficientHistory(t *testing.T) {
+	det := NewPivotDetector(120, 5)
+	res := det.Detect(dayEntries([]float64{10, 9, 8}))
+	if res.Confirmed {
+		t.Fatal("expected no confirmed pivot with insufficient history")
+	}
+}
+
+func TestPassesPivotFilter(t *testing.T) {
+	pivot := PivotResult{Confirmed: true, PivotPrice: 100}
+	if !PassesPivotFilter(pivot, 105, 10) {
+		t.Fatal("5% above pivot should pass a 10% filter")
+	}
+	if PassesPivotFilter(pivot, 120, 10) {
+		t.Fatal("20% above pivot should fail a 10% filter")
+	}
+	if PassesPivotFilter(PivotResult{}, 105, 10) {
+		t.Fatal("unconfirmed pivot should never pass the filter")
+	}
+}
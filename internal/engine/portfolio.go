@@ -19,6 +19,7 @@ type PortfolioPnL struct {
 	OpenPositions []OpenPosition    `json:"open_positions"`
 	Coverage      MatchingCoverage  `json:"coverage"`
 	Settings      PortfolioSettings `json:"settings"`
+	RollingSeries RollingSeries     `json:"rolling_series"`
 }
 
 // PortfolioPnLOptions controls realized P&L matching behavior.
@@ -28,6 +29,38 @@ type PortfolioPnLOptions struct {
 	BrokerFeePercent     float64
 	LedgerLimit          int
 	IncludeUnmatchedSell bool // legacy mode: treat unmatched sells as zero-cost proceeds
+
+	// CostBasisMethod selects how sells are matched against open buy lots:
+	// "fifo" (default), "lifo", "wac" (weighted-average cost), "hifo"
+	// (highest-cost-first), or "spec_id" (explicit lot assignment via
+	// SpecLotAssignments, falling back to FIFO for any sell it doesn't cover).
+	CostBasisMethod string
+	// SpecLotAssignments maps a sell transaction ID to the buy transaction
+	// ID it should be matched against. Only consulted when CostBasisMethod
+	// is "spec_id".
+	SpecLotAssignments map[int64]int64
+
+	// StartingCapital is the caller-supplied deployed capital used to
+	// compute CAGR. CAGR is left at 0 when this is <= 0.
+	StartingCapital float64
+	// OmegaThreshold is the minimum acceptable daily return (theta) used by
+	// the Omega ratio. Defaults to 0 (break-even) when unset.
+	OmegaThreshold float64
+
+	// PriceOracle supplies live market bid prices for mark-to-market
+	// unrealized P&L on open positions. Left nil, open positions simply
+	// carry zero unrealized P&L (degrades gracefully, no error).
+	PriceOracle PriceOracle
+	// LocationRegions maps a station/structure LocationID to the region it
+	// trades in, so PriceOracle lookups know which region's order book to
+	// consult for an open lot sitting at that location. Only consulted
+	// when PriceOracle is set.
+	LocationRegions map[int64]int64
+
+	// RollingWindowDays is the trailing window used to compute RollingSeries.
+	// Defaults to DefaultRollingWindowDays; clamped to
+	// [MinRollingWindowDays, MaxRollingWindowDays].
+	RollingWindowDays int
 }
 
 // PortfolioSettings is echoed back in API responses for traceability.
@@ -37,6 +70,8 @@ type PortfolioSettings struct {
 	BrokerFeePercent     float64 `json:"broker_fee_percent"`
 	LedgerLimit          int     `json:"ledger_limit"`
 	IncludeUnmatchedSell bool    `json:"include_unmatched_sell"`
+	CostBasisMethod      string  `json:"cost_basis_method"`
+	RollingWindowDays    int     `json:"rolling_window_days"`
 }
 
 // MatchingCoverage describes how much sell flow had known cost basis.
@@ -89,6 +124,13 @@ type OpenPosition struct {
 	AvgCost       float64 `json:"avg_cost"`
 	CostBasis     float64 `json:"cost_basis"`
 	OldestLotDate string  `json:"oldest_lot_date"`
+
+	// Mark-to-market fields, populated only when PortfolioPnLOptions.PriceOracle
+	// and LocationRegions resolve a live bid for this lot's type/location.
+	MarketPrice      float64 `json:"market_price"`
+	UnrealizedPnL    float64 `json:"unrealized_pnl"`
+	UnrealizedPnLPct float64 `json:"unrealized_pnl_pct"`
+	DaysHeld         int     `json:"days_held"`
 }
 
 // DailyPnLEntry represents one day's realized trading activity.
@@ -136,6 +178,24 @@ type PortfolioPnLStats struct {
 	OpenCostBasis    float64 `json:"open_cost_basis"`
 	TotalFees        float64 `json:"total_fees"`
 	TotalTaxes       float64 `json:"total_taxes"`
+
+	// Extended risk/return battery
+	SortinoRatio   float64 `json:"sortino_ratio"`    // annualized: mean/downside-deviation * sqrt(365)
+	OmegaRatio     float64 `json:"omega_ratio"`      // gains above theta / losses below theta
+	VaR95Pct       float64 `json:"var_95_pct"`       // historical 95% daily VaR, ISK (positive = loss)
+	VaR99Pct       float64 `json:"var_99_pct"`       // historical 99% daily VaR, ISK (positive = loss)
+	CVaR95Pct      float64 `json:"cvar_95_pct"`      // average loss beyond VaR95
+	CVaR99Pct      float64 `json:"cvar_99_pct"`      // average loss beyond VaR99
+	UlcerIndex     float64 `json:"ulcer_index"`      // sqrt(mean(drawdown_pct^2))
+	UlcerPerfIndex float64 `json:"ulcer_perf_index"` // annualized return % / Ulcer Index
+	CAGRPercent    float64 `json:"cagr_percent"`     // requires PortfolioPnLOptions.StartingCapital
+	AvgDrawdownPct float64 `json:"avg_drawdown_pct"` // mean depth of discrete drawdown episodes
+	KRatio         float64 `json:"k_ratio"`          // slope of cumulative P&L regression / its standard error * sqrt(n)
+
+	// Mark-to-market, requires PortfolioPnLOptions.PriceOracle + LocationRegions.
+	TotalUnrealizedPnL  float64 `json:"total_unrealized_pnl"`  // sum of unrealized P&L across open positions
+	TotalEquity         float64 `json:"total_equity"`          // TotalPnL + OpenCostBasis + TotalUnrealizedPnL
+	NetLiquidationValue float64 `json:"net_liquidation_value"` // current market value of open positions
 }
 
 // StationPnL is a per-station breakdown of trading activity.
@@ -163,6 +223,15 @@ type ItemPnL struct {
 	Transactions  int     `json:"transactions"`
 }
 
+// Supported PortfolioPnLOptions.CostBasisMethod values.
+const (
+	CostBasisFIFO   = "fifo"
+	CostBasisLIFO   = "lifo"
+	CostBasisWAC    = "wac"
+	CostBasisHIFO   = "hifo"
+	CostBasisSpecID = "spec_id"
+)
+
 type portfolioTx struct {
 	tx esi.WalletTransaction
 	t  time.Time
@@ -204,6 +273,13 @@ func normalizePortfolioOptions(opt PortfolioPnLOptions) PortfolioPnLOptions {
 	if opt.LedgerLimit < 0 {
 		opt.LedgerLimit = 0 // unlimited
 	}
+	switch opt.CostBasisMethod {
+	case CostBasisLIFO, CostBasisWAC, CostBasisHIFO, CostBasisSpecID:
+		// valid, leave as-is
+	default:
+		opt.CostBasisMethod = CostBasisFIFO
+	}
+	opt.RollingWindowDays = normalizeRollingWindowDays(opt.RollingWindowDays)
 	return opt
 }
 
@@ -234,7 +310,10 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 			BrokerFeePercent:     opt.BrokerFeePercent,
 			LedgerLimit:          opt.LedgerLimit,
 			IncludeUnmatchedSell: opt.IncludeUnmatchedSell,
+			CostBasisMethod:      opt.CostBasisMethod,
+			RollingWindowDays:    opt.RollingWindowDays,
 		},
+		RollingSeries: RollingSeries{Dates: []string{}},
 	}
 	if len(txns) == 0 {
 		return out
@@ -312,7 +391,7 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 		inLookback := !rec.t.Before(cutoff)
 
 		if tx.IsBuy {
-			buyQueues[tx.TypeID] = append(buyQueues[tx.TypeID], portfolioBuyLot{
+			newLot := portfolioBuyLot{
 				TransactionID: tx.TransactionID,
 				Date:          rec.t,
 				TypeID:        tx.TypeID,
@@ -321,7 +400,12 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 				LocationName:  tx.LocationName,
 				UnitPrice:     tx.UnitPrice,
 				Remaining:     tx.Quantity,
-			})
+			}
+			if opt.CostBasisMethod == CostBasisWAC {
+				buyQueues[tx.TypeID] = []portfolioBuyLot{mergeWACLot(buyQueues[tx.TypeID], newLot)}
+			} else {
+				buyQueues[tx.TypeID] = append(buyQueues[tx.TypeID], newLot)
+			}
 			continue
 		}
 
@@ -334,7 +418,8 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 		}
 
 		for remaining > 0 && len(queue) > 0 {
-			lot := &queue[0]
+			idx := nextMatchLotIndex(queue, opt.CostBasisMethod, tx.TransactionID, opt.SpecLotAssignments)
+			lot := &queue[idx]
 			matched := lot.Remaining
 			if matched > remaining {
 				matched = remaining
@@ -343,7 +428,7 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 			lot.Remaining -= matched
 			remaining -= matched
 			if lot.Remaining <= 0 {
-				queue = queue[1:]
+				queue = append(queue[:idx], queue[idx+1:]...)
 			}
 
 			if !inLookback {
@@ -595,15 +680,30 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 
 	if summary.TotalDays >= 2 {
 		dailyPnLs := make([]float64, len(days))
+		drawdownPcts := make([]float64, len(days))
+		cumulativeSeries := make([]float64, len(days))
 		for i, d := range days {
 			dailyPnLs[i] = d.NetPnL
+			drawdownPcts[i] = d.DrawdownPct
+			cumulativeSeries[i] = d.CumulativePnL
 		}
 		mu := mean(dailyPnLs)
 		sigma := math.Sqrt(variance(dailyPnLs))
 		if sigma > 0 {
 			summary.SharpeRatio = (mu / sigma) * math.Sqrt(365)
 		}
+
+		summary.SortinoRatio = sortinoRatio(dailyPnLs)
+		summary.OmegaRatio = omegaRatio(dailyPnLs, opt.OmegaThreshold)
+		summary.VaR95Pct = historicalVaR(dailyPnLs, 0.95)
+		summary.VaR99Pct = historicalVaR(dailyPnLs, 0.99)
+		summary.CVaR95Pct = historicalCVaR(dailyPnLs, 0.95)
+		summary.CVaR99Pct = historicalCVaR(dailyPnLs, 0.99)
+		summary.UlcerIndex = ulcerIndex(drawdownPcts)
+		summary.AvgDrawdownPct = avgDrawdownPct(cumulativeSeries)
+		summary.KRatio = kRatio(cumulativeSeries)
 	}
+	summary.CAGRPercent = cagrPercent(summary.TotalPnL, opt.StartingCapital, summary.TotalDays)
 
 	summary.MaxDrawdownISK = -maxDrawdownISK
 	if cumulativePeak > 0 {
@@ -619,9 +719,13 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 		}
 	}
 
-	if summary.MaxDrawdownISK > 0 && summary.TotalDays > 0 {
+	if summary.TotalDays > 0 {
 		annualizedReturn := summary.TotalPnL * 365 / float64(summary.TotalDays)
-		summary.CalmarRatio = annualizedReturn / summary.MaxDrawdownISK
+		if summary.MaxDrawdownISK > 0 {
+			summary.CalmarRatio = annualizedReturn / summary.MaxDrawdownISK
+		}
+		annualizedReturnPct := summary.ROIPercent * 365 / float64(summary.TotalDays)
+		summary.UlcerPerfIndex = ulcerPerformanceIndex(annualizedReturnPct, summary.UlcerIndex)
 	}
 	if grossLoss > 0 {
 		summary.ProfitFactor = grossProfit / grossLoss
@@ -735,7 +839,9 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 		}
 	}
 
+	priceCache := newOraclePriceCache(opt.PriceOracle)
 	openPositions := make([]OpenPosition, 0, len(openMap))
+	totalUnrealized := 0.0
 	for _, a := range openMap {
 		if a == nil || a.quantity <= 0 {
 			continue
@@ -744,7 +850,7 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 		if a.quantity > 0 {
 			avgCost = a.costBasis / float64(a.quantity)
 		}
-		openPositions = append(openPositions, OpenPosition{
+		pos := OpenPosition{
 			TypeID:        a.typeID,
 			TypeName:      a.typeName,
 			LocationID:    a.locationID,
@@ -753,14 +859,29 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 			AvgCost:       avgCost,
 			CostBasis:     a.costBasis,
 			OldestLotDate: a.oldest.Format("2006-01-02"),
-		})
+		}
+		if regionID, ok := opt.LocationRegions[a.locationID]; ok {
+			if price, ok := priceCache.bid(a.typeID, regionID); ok {
+				pos.MarketPrice = price
+				pos.UnrealizedPnL = price*float64(a.quantity) - a.costBasis
+				if a.costBasis > 0 {
+					pos.UnrealizedPnLPct = pos.UnrealizedPnL / a.costBasis * 100
+				}
+				pos.DaysHeld = int(now.Sub(a.oldest).Hours() / 24)
+			}
+		}
+		openPositions = append(openPositions, pos)
 		totalOpenCost += a.costBasis
+		totalUnrealized += pos.UnrealizedPnL
 	}
 	sort.Slice(openPositions, func(i, j int) bool {
 		return openPositions[i].CostBasis > openPositions[j].CostBasis
 	})
 	summary.OpenPositions = len(openPositions)
 	summary.OpenCostBasis = totalOpenCost
+	summary.TotalUnrealizedPnL = totalUnrealized
+	summary.NetLiquidationValue = totalOpenCost + totalUnrealized
+	summary.TotalEquity = summary.TotalPnL + summary.NetLiquidationValue
 
 	// Ledger newest first.
 	sort.Slice(ledger, func(i, j int) bool {
@@ -785,6 +906,7 @@ func ComputePortfolioPnLWithOptions(txns []esi.WalletTransaction, opt PortfolioP
 	out.TopStations = stations
 	out.Ledger = ledger
 	out.OpenPositions = openPositions
+	out.RollingSeries = computeRollingSeries(days, opt.RollingWindowDays)
 	return out
 }
 
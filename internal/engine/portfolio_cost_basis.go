@@ -0,0 +1,57 @@
+package engine
+
+// mergeWACLot folds a newly-bought lot into the single running
+// weighted-average-cost lot for its type. The existing queue is expected to
+// hold at most one lot under CostBasisWAC; the merged lot's date is kept at
+// the earliest contributing buy so holding-period stats stay meaningful.
+func mergeWACLot(queue []portfolioBuyLot, newLot portfolioBuyLot) portfolioBuyLot {
+	if len(queue) == 0 {
+		return newLot
+	}
+	existing := queue[0]
+	totalQty := existing.Remaining + newLot.Remaining
+	if totalQty <= 0 {
+		return newLot
+	}
+	weightedPrice := (existing.UnitPrice*float64(existing.Remaining) + newLot.UnitPrice*float64(newLot.Remaining)) / float64(totalQty)
+
+	merged := newLot
+	merged.UnitPrice = weightedPrice
+	merged.Remaining = totalQty
+	if existing.Date.Before(newLot.Date) {
+		merged.Date = existing.Date
+	}
+	return merged
+}
+
+// nextMatchLotIndex picks which lot in queue a sell should consume next,
+// according to the configured cost-basis method. Falls back to FIFO (index
+// 0) for fifo/wac (wac always has a single lot) and whenever spec_id has no
+// assignment covering this sell.
+func nextMatchLotIndex(queue []portfolioBuyLot, method string, sellTxID int64, specAssignments map[int64]int64) int {
+	switch method {
+	case CostBasisLIFO:
+		return len(queue) - 1
+	case CostBasisHIFO:
+		best := 0
+		for i := 1; i < len(queue); i++ {
+			if queue[i].UnitPrice > queue[best].UnitPrice {
+				best = i
+			} else if queue[i].UnitPrice == queue[best].UnitPrice && queue[i].Date.Before(queue[best].Date) {
+				best = i
+			}
+		}
+		return best
+	case CostBasisSpecID:
+		if buyTxID, ok := specAssignments[sellTxID]; ok {
+			for i, lot := range queue {
+				if lot.TransactionID == buyTxID {
+					return i
+				}
+			}
+		}
+		return 0
+	default: // fifo, wac
+		return 0
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func lot(txID int64, unitPrice float64, remaining int32, date time.Time) portfolioBuyLot {
+	return portfolioBuyLot{TransactionID: txID, UnitPrice: unitPrice, Remaining: remaining, Date: date}
+}
+
+func TestNextMatchLotIndex_FIFOTakesOldest(t *testing.T) {
+	queue := []portfolioBuyLot{lot(1, 5, 10, time.Unix(1, 0)), lot(2, 6, 10, time.Unix(2, 0))}
+	if got := nextMatchLotIndex(queue, CostBasisFIFO, 0, nil); got != 0 {
+		t.Fatalf("FIFO index = %d, want 0", got)
+	}
+}
+
+func TestNextMatchLotIndex_LIFOTakesNewest(t *testing.T) {
+	queue := []portfolioBuyLot{lot(1, 5, 10, time.Unix(1, 0)), lot(2, 6, 10, time.Unix(2, 0))}
+	if got := nextMatchLotIndex(queue, CostBasisLIFO, 0, nil); got != 1 {
+		t.Fatalf("LIFO index = %d, want 1", got)
+	}
+}
+
+func TestNextMatchLotIndex_HIFOTakesHighestPrice(t *testing.T) {
+	queue := []portfolioBuyLot{
+		lot(1, 5, 10, time.Unix(1, 0)),
+		lot(2, 9, 10, time.Unix(2, 0)),
+		lot(3, 7, 10, time.Unix(3, 0)),
+	}
+	if got := nextMatchLotIndex(queue, CostBasisHIFO, 0, nil); got != 1 {
+		t.Fatalf("HIFO index = %d, want 1 (unit price 9)", got)
+	}
+}
+
+func TestNextMatchLotIndex_HIFOTiebreaksOnEarlierDate(t *testing.T) {
+	queue := []portfolioBuyLot{
+		lot(1, 5, 10, time.Unix(2, 0)),
+		lot(2, 5, 10, time.Unix(1, 0)), // same price, earlier date
+	}
+	if got := nextMatchLotIndex(queue, CostBasisHIFO, 0, nil); got != 1 {
+		t.Fatalf("HIFO tiebreak index = %d, want 1 (earlier date)", got)
+	}
+}
+
+func TestNextMatchLotIndex_SpecIDMatchesAssignedLot(t *testing.T) {
+	queue := []portfolioBuyLot{lot(10, 5, 10, time.Unix(1, 0)), lot(20, 6, 10, time.Unix(2, 0))}
+	assignments := map[int64]int64{100: 20}
+	if got := nextMatchLotIndex(queue, CostBasisSpecID, 100, assignments); got != 1 {
+		t.Fatalf("spec_id index = %d, want 1 (assigned buy tx 20)", got)
+	}
+}
+
+func TestNextMatchLotIndex_SpecIDFallsBackToFIFO(t *testing.T) {
+	queue := []portfolioBuyLot{lot(10, 5, 10, time.Unix(1, 0)), lot(20, 6, 10, time.Unix(2, 0))}
+	if got := nextMatchLotIndex(queue, CostBasisSpecID, 999, map[int64]int64{}); got != 0 {
+		t.Fatalf("spec_id fallback index = %d, want 0 (FIFO)", got)
+	}
+}
+
+func TestMergeWACLot_WeightedAverage(t *testing.T) {
+	existing := lot(1, 10, 100, time.Unix(1, 0))
+	incoming := lot(2, 20, 100, time.Unix(2, 0))
+	merged := mergeWACLot([]portfolioBuyLot{existing}, incoming)
+
+	if merged.Remaining != 200 {
+		t.Fatalf("merged.Remaining = %d, want 200", merged.Remaining)
+	}
+	if merged.UnitPrice != 15 {
+		t.Fatalf("merged.UnitPrice = %v, want 15 (equal-weight average of 10 and 20)", merged.UnitPrice)
+	}
+	if !merged.Date.Equal(existing.Date) {
+		t.Errorf("merged.Date = %v, want the earlier existing date %v", merged.Date, existing.Date)
+	}
+}
+
+func TestMergeWACLot_EmptyQueueReturnsNewLotUnchanged(t *testing.T) {
+	incoming := lot(1, 10, 100, time.Unix(1, 0))
+	merged := mergeWACLot(nil, incoming)
+	if merged != incoming {
+		t.Fatalf("mergeWACLot with empty queue = %+v, want %+v", merged, incoming)
+	}
+}
@@ -0,0 +1,217 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ledgerRecordType discriminates the two row shapes ExportLedgerCSV writes
+// into a single file, so a corp-level importer can reassemble both realized
+// trades and still-open inventory from one export without a second file.
+const (
+	ledgerRecordTypeRealized = "realized"
+	ledgerRecordTypeOpen     = "open"
+)
+
+// ledgerCSVHeader is the union of every RealizedTrade and OpenPosition
+// column. Each row only populates the columns for its own record_type;
+// the rest are left blank.
+var ledgerCSVHeader = []string{
+	"record_type",
+	"type_id", "type_name",
+	"quantity",
+	"buy_transaction_id", "sell_transaction_id",
+	"buy_date", "sell_date",
+	"holding_days",
+	"buy_location_id", "buy_location_name",
+	"sell_location_id", "sell_location_name",
+	"buy_unit_price", "sell_unit_price",
+	"buy_gross", "sell_gross",
+	"buy_fee", "sell_broker_fee", "sell_tax",
+	"buy_total", "sell_total",
+	"realized_pnl", "margin_percent",
+	"unmatched",
+	"avg_cost", "cost_basis", "oldest_lot_date",
+	"market_price", "unrealized_pnl", "unrealized_pnl_pct", "days_held",
+}
+
+// ExportLedgerCSV writes p's realized ledger and open positions to w as a
+// single CSV file, discriminated by a record_type column, so the full
+// FIFO/LIFO/etc. matching history (including which buy transaction ID
+// funded which sell) is auditable offline and re-importable for
+// cross-character aggregation via MergePortfolios.
+func ExportLedgerCSV(w io.Writer, p *PortfolioPnL) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ledgerCSVHeader); err != nil {
+		return err
+	}
+
+	for _, t := range p.Ledger {
+		row := []string{
+			ledgerRecordTypeRealized,
+			strconv.FormatInt(int64(t.TypeID), 10), t.TypeName,
+			strconv.FormatInt(int64(t.Quantity), 10),
+			strconv.FormatInt(t.BuyTransactionID, 10), strconv.FormatInt(t.SellTransactionID, 10),
+			t.BuyDate, t.SellDate,
+			strconv.Itoa(t.HoldingDays),
+			strconv.FormatInt(t.BuyLocationID, 10), t.BuyLocationName,
+			strconv.FormatInt(t.SellLocationID, 10), t.SellLocationName,
+			formatFloat(t.BuyUnitPrice), formatFloat(t.SellUnitPrice),
+			formatFloat(t.BuyGross), formatFloat(t.SellGross),
+			formatFloat(t.BuyFee), formatFloat(t.SellBrokerFee), formatFloat(t.SellTax),
+			formatFloat(t.BuyTotal), formatFloat(t.SellTotal),
+			formatFloat(t.RealizedPnL), formatFloat(t.MarginPercent),
+			strconv.FormatBool(t.Unmatched),
+			"", "", "",
+			"", "", "", "",
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, pos := range p.OpenPositions {
+		row := []string{
+			ledgerRecordTypeOpen,
+			strconv.FormatInt(int64(pos.TypeID), 10), pos.TypeName,
+			strconv.FormatInt(pos.Quantity, 10),
+			"", "",
+			"", "",
+			"",
+			"", "",
+			strconv.FormatInt(pos.LocationID, 10), pos.LocationName,
+			"", "",
+			"", "",
+			"", "", "",
+			"", "",
+			"", "",
+			"",
+			formatFloat(pos.AvgCost), formatFloat(pos.CostBasis), pos.OldestLotDate,
+			formatFloat(pos.MarketPrice), formatFloat(pos.UnrealizedPnL), formatFloat(pos.UnrealizedPnLPct), strconv.Itoa(pos.DaysHeld),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportLedgerCSV reads back a file written by ExportLedgerCSV, splitting
+// it into realized trades and open positions by its record_type column.
+func ImportLedgerCSV(r io.Reader) ([]RealizedTrade, []OpenPosition, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading ledger csv header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[name] = i
+	}
+
+	get := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var trades []RealizedTrade
+	var positions []OpenPosition
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading ledger csv row: %w", err)
+		}
+
+		typeID, _ := strconv.ParseInt(get(row, "type_id"), 10, 32)
+
+		switch get(row, "record_type") {
+		case ledgerRecordTypeOpen:
+			locationID, _ := strconv.ParseInt(get(row, "buy_location_id"), 10, 64)
+			if locationID == 0 {
+				locationID, _ = strconv.ParseInt(get(row, "sell_location_id"), 10, 64)
+			}
+			quantity, _ := strconv.ParseInt(get(row, "quantity"), 10, 64)
+			daysHeld, _ := strconv.Atoi(get(row, "days_held"))
+			positions = append(positions, OpenPosition{
+				TypeID:           int32(typeID),
+				TypeName:         get(row, "type_name"),
+				LocationID:       locationID,
+				LocationName:     firstNonEmpty(get(row, "buy_location_name"), get(row, "sell_location_name")),
+				Quantity:         quantity,
+				AvgCost:          parseFloat(get(row, "avg_cost")),
+				CostBasis:        parseFloat(get(row, "cost_basis")),
+				OldestLotDate:    get(row, "oldest_lot_date"),
+				MarketPrice:      parseFloat(get(row, "market_price")),
+				UnrealizedPnL:    parseFloat(get(row, "unrealized_pnl")),
+				UnrealizedPnLPct: parseFloat(get(row, "unrealized_pnl_pct")),
+				DaysHeld:         daysHeld,
+			})
+		default: // ledgerRecordTypeRealized, or unlabeled rows from older exports
+			quantity, _ := strconv.ParseInt(get(row, "quantity"), 10, 32)
+			buyTxID, _ := strconv.ParseInt(get(row, "buy_transaction_id"), 10, 64)
+			sellTxID, _ := strconv.ParseInt(get(row, "sell_transaction_id"), 10, 64)
+			buyLocationID, _ := strconv.ParseInt(get(row, "buy_location_id"), 10, 64)
+			sellLocationID, _ := strconv.ParseInt(get(row, "sell_location_id"), 10, 64)
+			holdingDays, _ := strconv.Atoi(get(row, "holding_days"))
+			unmatched, _ := strconv.ParseBool(get(row, "unmatched"))
+			trades = append(trades, RealizedTrade{
+				TypeID:            int32(typeID),
+				TypeName:          get(row, "type_name"),
+				Quantity:          int32(quantity),
+				BuyTransactionID:  buyTxID,
+				SellTransactionID: sellTxID,
+				BuyDate:           get(row, "buy_date"),
+				SellDate:          get(row, "sell_date"),
+				HoldingDays:       holdingDays,
+				BuyLocationID:     buyLocationID,
+				BuyLocationName:   get(row, "buy_location_name"),
+				SellLocationID:    sellLocationID,
+				SellLocationName:  get(row, "sell_location_name"),
+				BuyUnitPrice:      parseFloat(get(row, "buy_unit_price")),
+				SellUnitPrice:     parseFloat(get(row, "sell_unit_price")),
+				BuyGross:          parseFloat(get(row, "buy_gross")),
+				SellGross:         parseFloat(get(row, "sell_gross")),
+				BuyFee:            parseFloat(get(row, "buy_fee")),
+				SellBrokerFee:     parseFloat(get(row, "sell_broker_fee")),
+				SellTax:           parseFloat(get(row, "sell_tax")),
+				BuyTotal:          parseFloat(get(row, "buy_total")),
+				SellTotal:         parseFloat(get(row, "sell_total")),
+				RealizedPnL:       parseFloat(get(row, "realized_pnl")),
+				MarginPercent:     parseFloat(get(row, "margin_percent")),
+				Unmatched:         unmatched,
+			})
+		}
+	}
+
+	return trades, positions, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
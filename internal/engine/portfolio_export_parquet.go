@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRealizedTradeRowGroupSize is small since a single character's
+// ledger rarely exceeds a few thousand rows; this keeps memory use
+// predictable rather than tuned for bulk analytics workloads.
+const parquetRealizedTradeRowGroupSize = 128
+
+// parquetRealizedTrade mirrors RealizedTrade with parquet-go struct tags.
+// Kept as its own type (rather than tagging RealizedTrade directly) so the
+// JSON API shape isn't coupled to the columnar export schema.
+type parquetRealizedTrade struct {
+	TypeID            int32   `parquet:"name=type_id, type=INT32"`
+	TypeName          string  `parquet:"name=type_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Quantity          int32   `parquet:"name=quantity, type=INT32"`
+	BuyTransactionID  int64   `parquet:"name=buy_transaction_id, type=INT64"`
+	SellTransactionID int64   `parquet:"name=sell_transaction_id, type=INT64"`
+	BuyDate           string  `parquet:"name=buy_date, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SellDate          string  `parquet:"name=sell_date, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HoldingDays       int32   `parquet:"name=holding_days, type=INT32"`
+	BuyLocationID     int64   `parquet:"name=buy_location_id, type=INT64"`
+	BuyLocationName   string  `parquet:"name=buy_location_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SellLocationID    int64   `parquet:"name=sell_location_id, type=INT64"`
+	SellLocationName  string  `parquet:"name=sell_location_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BuyUnitPrice      float64 `parquet:"name=buy_unit_price, type=DOUBLE"`
+	SellUnitPrice     float64 `parquet:"name=sell_unit_price, type=DOUBLE"`
+	BuyGross          float64 `parquet:"name=buy_gross, type=DOUBLE"`
+	SellGross         float64 `parquet:"name=sell_gross, type=DOUBLE"`
+	BuyFee            float64 `parquet:"name=buy_fee, type=DOUBLE"`
+	SellBrokerFee     float64 `parquet:"name=sell_broker_fee, type=DOUBLE"`
+	SellTax           float64 `parquet:"name=sell_tax, type=DOUBLE"`
+	BuyTotal          float64 `parquet:"name=buy_total, type=DOUBLE"`
+	SellTotal         float64 `parquet:"name=sell_total, type=DOUBLE"`
+	RealizedPnL       float64 `parquet:"name=realized_pnl, type=DOUBLE"`
+	MarginPercent     float64 `parquet:"name=margin_percent, type=DOUBLE"`
+	Unmatched         bool    `parquet:"name=unmatched, type=BOOLEAN"`
+}
+
+// ExportLedgerParquet writes p's realized ledger (open positions are left
+// to ExportLedgerCSV, which is the audit-trail export; this one targets
+// bulk columnar analysis of realized trades) to w in Parquet format,
+// writing directly to w via NewParquetWriterFromWriter rather than
+// buffering through an in-memory filesystem.
+func ExportLedgerParquet(w io.Writer, p *PortfolioPnL) error {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRealizedTrade), 4)
+	if err != nil {
+		return fmt.Errorf("creating parquet writer: %w", err)
+	}
+	pw.RowGroupSize = parquetRealizedTradeRowGroupSize * 1024
+
+	for _, t := range p.Ledger {
+		row := parquetRealizedTrade{
+			TypeID:            t.TypeID,
+			TypeName:          t.TypeName,
+			Quantity:          t.Quantity,
+			BuyTransactionID:  t.BuyTransactionID,
+			SellTransactionID: t.SellTransactionID,
+			BuyDate:           t.BuyDate,
+			SellDate:          t.SellDate,
+			HoldingDays:       int32(t.HoldingDays),
+			BuyLocationID:     t.BuyLocationID,
+			BuyLocationName:   t.BuyLocationName,
+			SellLocationID:    t.SellLocationID,
+			SellLocationName:  t.SellLocationName,
+			BuyUnitPrice:      t.BuyUnitPrice,
+			SellUnitPrice:     t.SellUnitPrice,
+			BuyGross:          t.BuyGross,
+			SellGross:         t.SellGross,
+			BuyFee:            t.BuyFee,
+			SellBrokerFee:     t.SellBrokerFee,
+			SellTax:           t.SellTax,
+			BuyTotal:          t.BuyTotal,
+			SellTotal:         t.SellTotal,
+			RealizedPnL:       t.RealizedPnL,
+			MarginPercent:     t.MarginPercent,
+			Unmatched:         t.Unmatched,
+		}
+		if err := pw.Write(row); err != nil {
+			return fmt.Errorf("writing parquet row: %w", err)
+		}
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		return fmt.Errorf("finalizing parquet file: %w", err)
+	}
+	return nil
+}
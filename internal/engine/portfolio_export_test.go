@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExportImportLedgerCSV_RoundTripsRealizedTrades(t *testing.T) {
+	p := &PortfolioPnL{
+		Ledger: []RealizedTrade{
+			{
+				TypeID: 34, TypeName: "Tritanium", Quantity: 100,
+				BuyTransactionID: 1, SellTransactionID: 2,
+				BuyDate: "2026-01-01T00:00:00Z", SellDate: "2026-01-02T00:00:00Z",
+				HoldingDays:  1,
+				BuyUnitPrice: 5, SellUnitPrice: 6,
+				BuyGross: 500, SellGross: 600,
+				BuyTotal: 500, SellTotal: 600,
+				RealizedPnL: 100, MarginPercent: 20,
+			},
+			{
+				TypeID: 35, TypeName: "Pyerite", Quantity: 50,
+				SellTransactionID: 3, SellDate: "2026-01-03T00:00:00Z",
+				SellUnitPrice: 10, SellGross: 500, SellTotal: 500,
+				RealizedPnL: 500, Unmatched: true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportLedgerCSV(&buf, p); err != nil {
+		t.Fatalf("ExportLedgerCSV: %v", err)
+	}
+
+	trades, positions, err := ImportLedgerCSV(&buf)
+	if err != nil {
+		t.Fatalf("ImportLedgerCSV: %v", err)
+	}
+	if len(positions) != 0 {
+		t.Fatalf("positions = %d, want 0", len(positions))
+	}
+	if len(trades) != 2 {
+		t.Fatalf("trades = %d, want 2", len(trades))
+	}
+	if trades[0] != p.Ledger[0] {
+		t.Errorf("trades[0] = %+v, want %+v", trades[0], p.Ledger[0])
+	}
+	if trades[1] != p.Ledger[1] {
+		t.Errorf("trades[1] = %+v, want %+v", trades[1], p.Ledger[1])
+	}
+}
+
+func TestExportImportLedgerCSV_RoundTripsOpenPositions(t *testing.T) {
+	p := &PortfolioPnL{
+		OpenPositions: []OpenPosition{
+			{
+				TypeID: 34, TypeName: "Tritanium",
+				LocationID: 60003760, LocationName: "Jita IV - Moon 4",
+				Quantity: 1000, AvgCost: 5.5, CostBasis: 5500,
+				OldestLotDate: "2026-01-01",
+				MarketPrice:   6, UnrealizedPnL: 500, UnrealizedPnLPct: 9.09, DaysHeld: 10,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportLedgerCSV(&buf, p); err != nil {
+		t.Fatalf("ExportLedgerCSV: %v", err)
+	}
+
+	trades, positions, err := ImportLedgerCSV(&buf)
+	if err != nil {
+		t.Fatalf("ImportLedgerCSV: %v", err)
+	}
+	if len(trades) != 0 {
+		t.Fatalf("trades = %d, want 0", len(trades))
+	}
+	if len(positions) != 1 {
+		t.Fatalf("positions = %d, want 1", len(positions))
+	}
+	if positions[0] != p.OpenPositions[0] {
+		t.Errorf("positions[0] = %+v, want %+v", positions[0], p.OpenPositions[0])
+	}
+}
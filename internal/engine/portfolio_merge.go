@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MergePortfolios combines several already-computed PortfolioPnL snapshots
+// (e.g. one per corp member) into a single aggregate view. It re-derives
+// the return and risk statistics from the merged daily series rather than
+// summing the per-portfolio summaries, since Sharpe/drawdown/VaR/etc. don't
+// combine additively across independent return series. Only the additive
+// counts RealizedTrades, RealizedQuantity, TotalFees and TotalTaxes are
+// summed from the members' summaries.
+//
+// It operates purely on the exported PortfolioPnL structures, not the
+// underlying wallet transactions, since corp-level callers assemble these
+// from per-character snapshots that may have been computed with different
+// PortfolioPnLOptions. As a consequence CAGRPercent and OmegaRatio - which
+// depend on options that aren't echoed back on the output (StartingCapital,
+// OmegaThreshold) - are recomputed using their zero-value defaults rather
+// than each member's original settings.
+func MergePortfolios(ps ...*PortfolioPnL) *PortfolioPnL {
+	out := &PortfolioPnL{
+		DailyPnL:      []DailyPnLEntry{},
+		TopItems:      []ItemPnL{},
+		TopStations:   []StationPnL{},
+		Ledger:        []RealizedTrade{},
+		OpenPositions: []OpenPosition{},
+		RollingSeries: RollingSeries{Dates: []string{}},
+	}
+	if len(ps) == 0 {
+		return out
+	}
+
+	type openKey struct {
+		typeID     int32
+		locationID int64
+	}
+
+	dayMap := make(map[string]*DailyPnLEntry)
+	itemMap := make(map[int32]*ItemPnL)
+	stationMap := make(map[int64]*StationPnL)
+	openMap := make(map[openKey]*OpenPosition)
+	coverage := MatchingCoverage{}
+	summary := PortfolioPnLStats{}
+	rollingWindowDays := 0
+
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+		for _, d := range p.DailyPnL {
+			entry, ok := dayMap[d.Date]
+			if !ok {
+				entry = &DailyPnLEntry{Date: d.Date}
+				dayMap[d.Date] = entry
+			}
+			entry.BuyTotal += d.BuyTotal
+			entry.SellTotal += d.SellTotal
+			entry.Transactions += d.Transactions
+		}
+		for _, item := range p.TopItems {
+			agg, ok := itemMap[item.TypeID]
+			if !ok {
+				agg = &ItemPnL{TypeID: item.TypeID, TypeName: item.TypeName}
+				itemMap[item.TypeID] = agg
+			}
+			agg.TotalBought += item.TotalBought
+			agg.TotalSold += item.TotalSold
+			agg.QtyBought += item.QtyBought
+			agg.QtySold += item.QtySold
+			agg.Transactions += item.Transactions
+		}
+		for _, st := range p.TopStations {
+			agg, ok := stationMap[st.LocationID]
+			if !ok {
+				agg = &StationPnL{LocationID: st.LocationID, LocationName: st.LocationName}
+				stationMap[st.LocationID] = agg
+			}
+			agg.TotalBought += st.TotalBought
+			agg.TotalSold += st.TotalSold
+			agg.Transactions += st.Transactions
+		}
+		for _, pos := range p.OpenPositions {
+			key := openKey{typeID: pos.TypeID, locationID: pos.LocationID}
+			agg, ok := openMap[key]
+			if !ok {
+				agg = &OpenPosition{
+					TypeID:        pos.TypeID,
+					TypeName:      pos.TypeName,
+					LocationID:    pos.LocationID,
+					LocationName:  pos.LocationName,
+					OldestLotDate: pos.OldestLotDate,
+				}
+				openMap[key] = agg
+			}
+			agg.Quantity += pos.Quantity
+			agg.CostBasis += pos.CostBasis
+			agg.UnrealizedPnL += pos.UnrealizedPnL
+			if pos.OldestLotDate != "" && (agg.OldestLotDate == "" || pos.OldestLotDate < agg.OldestLotDate) {
+				agg.OldestLotDate = pos.OldestLotDate
+			}
+		}
+
+		out.Ledger = append(out.Ledger, p.Ledger...)
+		coverage.TotalSellQty += p.Coverage.TotalSellQty
+		coverage.MatchedSellQty += p.Coverage.MatchedSellQty
+		coverage.UnmatchedSellQty += p.Coverage.UnmatchedSellQty
+		coverage.TotalSellValue += p.Coverage.TotalSellValue
+		coverage.MatchedSellValue += p.Coverage.MatchedSellValue
+		coverage.UnmatchedSellValue += p.Coverage.UnmatchedSellValue
+
+		summary.RealizedTrades += p.Summary.RealizedTrades
+		summary.RealizedQuantity += p.Summary.RealizedQuantity
+		summary.TotalFees += p.Summary.TotalFees
+		summary.TotalTaxes += p.Summary.TotalTaxes
+
+		if p.Settings.RollingWindowDays > rollingWindowDays {
+			rollingWindowDays = p.Settings.RollingWindowDays
+		}
+	}
+
+	if coverage.TotalSellQty > 0 {
+		coverage.MatchRateQtyPct = float64(coverage.MatchedSellQty) / float64(coverage.TotalSellQty) * 100
+	}
+	if coverage.TotalSellValue > 0 {
+		coverage.MatchRateValuePct = coverage.MatchedSellValue / coverage.TotalSellValue * 100
+	}
+	out.Coverage = coverage
+
+	days := make([]DailyPnLEntry, 0, len(dayMap))
+	for _, d := range dayMap {
+		d.NetPnL = d.SellTotal - d.BuyTotal
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	cumulative, cumulativePeak, maxDrawdownISK := 0.0, 0.0, 0.0
+	maxDrawdownPeakIdx, maxDrawdownTroughIdx, currentPeakIdx := 0, 0, 0
+	for i := range days {
+		cumulative += days[i].NetPnL
+		days[i].CumulativePnL = cumulative
+
+		if cumulative > cumulativePeak {
+			cumulativePeak = cumulative
+			currentPeakIdx = i
+		}
+
+		drawdownISK := cumulative - cumulativePeak
+		if cumulativePeak > 0 {
+			days[i].DrawdownPct = drawdownISK / cumulativePeak * 100
+		}
+
+		if drawdownISK < maxDrawdownISK {
+			maxDrawdownISK = drawdownISK
+			maxDrawdownPeakIdx = currentPeakIdx
+			maxDrawdownTroughIdx = i
+		}
+	}
+
+	summary.TotalDays = len(days)
+	if len(days) > 0 {
+		summary.BestDayPnL = days[0].NetPnL
+		summary.BestDayDate = days[0].Date
+		summary.WorstDayPnL = days[0].NetPnL
+		summary.WorstDayDate = days[0].Date
+	}
+
+	var grossProfit, grossLoss, totalWinISK, totalLossISK float64
+	for _, d := range days {
+		summary.TotalPnL += d.NetPnL
+		summary.TotalBought += d.BuyTotal
+		summary.TotalSold += d.SellTotal
+
+		if d.NetPnL > 0 {
+			summary.ProfitableDays++
+			grossProfit += d.NetPnL
+			totalWinISK += d.NetPnL
+		} else if d.NetPnL < 0 {
+			summary.LosingDays++
+			grossLoss += -d.NetPnL
+			totalLossISK += -d.NetPnL
+		}
+
+		if d.NetPnL > summary.BestDayPnL {
+			summary.BestDayPnL = d.NetPnL
+			summary.BestDayDate = d.Date
+		}
+		if d.NetPnL < summary.WorstDayPnL {
+			summary.WorstDayPnL = d.NetPnL
+			summary.WorstDayDate = d.Date
+		}
+	}
+
+	if summary.TotalDays > 0 {
+		summary.AvgDailyPnL = summary.TotalPnL / float64(summary.TotalDays)
+		summary.WinRate = float64(summary.ProfitableDays) / float64(summary.TotalDays) * 100
+	}
+
+	if len(days) > 0 {
+		var cumBuy, cumSell, capitalSum float64
+		for _, d := range days {
+			cumBuy += d.BuyTotal
+			cumSell += d.SellTotal
+			deployed := cumBuy - cumSell
+			if deployed > 0 {
+				capitalSum += deployed
+			}
+		}
+		avgCapital := capitalSum / float64(len(days))
+		if avgCapital > 0 {
+			summary.ROIPercent = summary.TotalPnL / avgCapital * 100
+		} else if summary.TotalBought > 0 {
+			summary.ROIPercent = summary.TotalPnL / summary.TotalBought * 100
+		}
+	}
+
+	if summary.TotalDays >= 2 {
+		dailyPnLs := make([]float64, len(days))
+		drawdownPcts := make([]float64, len(days))
+		cumulativeSeries := make([]float64, len(days))
+		for i, d := range days {
+			dailyPnLs[i] = d.NetPnL
+			drawdownPcts[i] = d.DrawdownPct
+			cumulativeSeries[i] = d.CumulativePnL
+		}
+		mu := mean(dailyPnLs)
+		sigma := math.Sqrt(variance(dailyPnLs))
+		if sigma > 0 {
+			summary.SharpeRatio = (mu / sigma) * math.Sqrt(365)
+		}
+
+		summary.SortinoRatio = sortinoRatio(dailyPnLs)
+		summary.OmegaRatio = omegaRatio(dailyPnLs, 0)
+		summary.VaR95Pct = historicalVaR(dailyPnLs, 0.95)
+		summary.VaR99Pct = historicalVaR(dailyPnLs, 0.99)
+		summary.CVaR95Pct = historicalCVaR(dailyPnLs, 0.95)
+		summary.CVaR99Pct = historicalCVaR(dailyPnLs, 0.99)
+		summary.UlcerIndex = ulcerIndex(drawdownPcts)
+		summary.AvgDrawdownPct = avgDrawdownPct(cumulativeSeries)
+		summary.KRatio = kRatio(cumulativeSeries)
+	}
+
+	summary.MaxDrawdownISK = -maxDrawdownISK
+	if cumulativePeak > 0 {
+		summary.MaxDrawdownPct = -maxDrawdownISK / cumulativePeak * 100
+	}
+	if maxDrawdownTroughIdx > maxDrawdownPeakIdx {
+		peakDate, errP := time.Parse("2006-01-02", days[maxDrawdownPeakIdx].Date)
+		troughDate, errT := time.Parse("2006-01-02", days[maxDrawdownTroughIdx].Date)
+		if errP == nil && errT == nil {
+			summary.MaxDrawdownDays = int(troughDate.Sub(peakDate).Hours() / 24)
+		} else {
+			summary.MaxDrawdownDays = maxDrawdownTroughIdx - maxDrawdownPeakIdx
+		}
+	}
+
+	if summary.TotalDays > 0 {
+		annualizedReturn := summary.TotalPnL * 365 / float64(summary.TotalDays)
+		if summary.MaxDrawdownISK > 0 {
+			summary.CalmarRatio = annualizedReturn / summary.MaxDrawdownISK
+		}
+		annualizedReturnPct := summary.ROIPercent * 365 / float64(summary.TotalDays)
+		summary.UlcerPerfIndex = ulcerPerformanceIndex(annualizedReturnPct, summary.UlcerIndex)
+	}
+	if grossLoss > 0 {
+		summary.ProfitFactor = grossProfit / grossLoss
+	}
+	if summary.ProfitableDays > 0 {
+		summary.AvgWin = totalWinISK / float64(summary.ProfitableDays)
+	}
+	if summary.LosingDays > 0 {
+		summary.AvgLoss = totalLossISK / float64(summary.LosingDays)
+	}
+	if summary.TotalDays > 0 {
+		winRate := float64(summary.ProfitableDays) / float64(summary.TotalDays)
+		lossRate := float64(summary.LosingDays) / float64(summary.TotalDays)
+		summary.ExpectancyPerTrade = winRate*summary.AvgWin - lossRate*summary.AvgLoss
+	}
+
+	items := make([]ItemPnL, 0, len(itemMap))
+	for _, item := range itemMap {
+		item.NetPnL = item.TotalSold - item.TotalBought
+		if item.QtyBought > 0 {
+			item.AvgBuyPrice = item.TotalBought / float64(item.QtyBought)
+		}
+		if item.QtySold > 0 {
+			item.AvgSellPrice = item.TotalSold / float64(item.QtySold)
+		}
+		if item.AvgBuyPrice > 0 && item.AvgSellPrice > 0 {
+			item.MarginPercent = (item.AvgSellPrice - item.AvgBuyPrice) / item.AvgBuyPrice * 100
+		}
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return absFloat(items[i].NetPnL) > absFloat(items[j].NetPnL)
+	})
+	if len(items) > 50 {
+		items = items[:50]
+	}
+
+	stations := make([]StationPnL, 0, len(stationMap))
+	for _, st := range stationMap {
+		st.NetPnL = st.TotalSold - st.TotalBought
+		stations = append(stations, *st)
+	}
+	sort.Slice(stations, func(i, j int) bool {
+		return absFloat(stations[i].NetPnL) > absFloat(stations[j].NetPnL)
+	})
+	if len(stations) > 20 {
+		stations = stations[:20]
+	}
+
+	openPositions := make([]OpenPosition, 0, len(openMap))
+	totalOpenCost, totalUnrealized := 0.0, 0.0
+	for _, pos := range openMap {
+		if pos.Quantity > 0 {
+			pos.AvgCost = pos.CostBasis / float64(pos.Quantity)
+		}
+		if pos.CostBasis > 0 {
+			pos.UnrealizedPnLPct = pos.UnrealizedPnL / pos.CostBasis * 100
+		}
+		openPositions = append(openPositions, *pos)
+		totalOpenCost += pos.CostBasis
+		totalUnrealized += pos.UnrealizedPnL
+	}
+	sort.Slice(openPositions, func(i, j int) bool {
+		return openPositions[i].CostBasis > openPositions[j].CostBasis
+	})
+	summary.OpenPositions = len(openPositions)
+	summary.OpenCostBasis = totalOpenCost
+	summary.TotalUnrealizedPnL = totalUnrealized
+	summary.NetLiquidationValue = totalOpenCost + totalUnrealized
+	summary.TotalEquity = summary.TotalPnL + summary.NetLiquidationValue
+	if len(openPositions) > 50 {
+		openPositions = openPositions[:50]
+	}
+
+	sort.Slice(out.Ledger, func(i, j int) bool {
+		if out.Ledger[i].SellDate == out.Ledger[j].SellDate {
+			if out.Ledger[i].SellTransactionID == out.Ledger[j].SellTransactionID {
+				return out.Ledger[i].BuyTransactionID > out.Ledger[j].BuyTransactionID
+			}
+			return out.Ledger[i].SellTransactionID > out.Ledger[j].SellTransactionID
+		}
+		return out.Ledger[i].SellDate > out.Ledger[j].SellDate
+	})
+
+	out.DailyPnL = days
+	out.Summary = summary
+	out.TopItems = items
+	out.TopStations = stations
+	out.OpenPositions = openPositions
+	out.Settings = PortfolioSettings{RollingWindowDays: normalizeRollingWindowDays(rollingWindowDays)}
+	out.RollingSeries = computeRollingSeries(days, out.Settings.RollingWindowDays)
+	return out
+}
@@ -0,0 +1,70 @@
+package engine
+
+import "testing"
+
+func TestMergePortfolios_NoInputsReturnsEmptyShell(t *testing.T) {
+	out := MergePortfolios()
+	if out.DailyPnL == nil || out.TopItems == nil || out.Ledger == nil {
+		t.Fatalf("MergePortfolios() with no inputs should return initialized empty slices, got %+v", out)
+	}
+}
+
+func TestMergePortfolios_SumsOverlappingDaysAcrossCharacters(t *testing.T) {
+	a := &PortfolioPnL{
+		DailyPnL: []DailyPnLEntry{
+			{Date: "2026-01-01", BuyTotal: 100, SellTotal: 150, NetPnL: 50, Transactions: 1},
+		},
+	}
+	b := &PortfolioPnL{
+		DailyPnL: []DailyPnLEntry{
+			{Date: "2026-01-01", BuyTotal: 200, SellTotal: 220, NetPnL: 20, Transactions: 2},
+		},
+	}
+
+	out := MergePortfolios(a, b)
+	if len(out.DailyPnL) != 1 {
+		t.Fatalf("len(DailyPnL) = %d, want 1 (same date merged)", len(out.DailyPnL))
+	}
+	day := out.DailyPnL[0]
+	if day.BuyTotal != 300 || day.SellTotal != 370 || day.NetPnL != 70 || day.Transactions != 3 {
+		t.Errorf("merged day = %+v, want BuyTotal=300 SellTotal=370 NetPnL=70 Transactions=3", day)
+	}
+	if out.Summary.TotalPnL != 70 {
+		t.Errorf("Summary.TotalPnL = %v, want 70", out.Summary.TotalPnL)
+	}
+}
+
+func TestMergePortfolios_CombinesOpenPositionsByTypeAndLocation(t *testing.T) {
+	a := &PortfolioPnL{
+		OpenPositions: []OpenPosition{
+			{TypeID: 34, LocationID: 1, Quantity: 100, CostBasis: 500, UnrealizedPnL: 10},
+		},
+	}
+	b := &PortfolioPnL{
+		OpenPositions: []OpenPosition{
+			{TypeID: 34, LocationID: 1, Quantity: 50, CostBasis: 260, UnrealizedPnL: 5},
+		},
+	}
+
+	out := MergePortfolios(a, b)
+	if len(out.OpenPositions) != 1 {
+		t.Fatalf("len(OpenPositions) = %d, want 1", len(out.OpenPositions))
+	}
+	pos := out.OpenPositions[0]
+	if pos.Quantity != 150 || pos.CostBasis != 760 {
+		t.Errorf("merged position = %+v, want Quantity=150 CostBasis=760", pos)
+	}
+	if out.Summary.TotalUnrealizedPnL != 15 {
+		t.Errorf("Summary.TotalUnrealizedPnL = %v, want 15", out.Summary.TotalUnrealizedPnL)
+	}
+}
+
+func TestMergePortfolios_SkipsNilPortfolios(t *testing.T) {
+	a := &PortfolioPnL{
+		DailyPnL: []DailyPnLEntry{{Date: "2026-01-01", NetPnL: 10}},
+	}
+	out := MergePortfolios(a, nil)
+	if len(out.DailyPnL) != 1 {
+		t.Fatalf("len(DailyPnL) = %d, want 1 (nil input skipped)", len(out.DailyPnL))
+	}
+}
@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// sortinoRatio is the annualized mean return over downside deviation, where
+// downside deviation is the RMS of min(r_i-target, 0) with target=0 (daily).
+// Zero-safe: returns 0 when there's no downside variance.
+func sortinoRatio(dailyPnLs []float64) float64 {
+	if len(dailyPnLs) == 0 {
+		return 0
+	}
+	mu := mean(dailyPnLs)
+
+	var sumSq float64
+	for _, r := range dailyPnLs {
+		d := math.Min(r, 0)
+		sumSq += d * d
+	}
+	downsideDev := math.Sqrt(sumSq / float64(len(dailyPnLs)))
+	if downsideDev == 0 {
+		return 0
+	}
+	return (mu / downsideDev) * math.Sqrt(365)
+}
+
+// omegaRatio is the ratio of gains above theta to losses below theta.
+// Zero-safe: returns 0 when there are no sub-theta losses to divide by.
+func omegaRatio(dailyPnLs []float64, theta float64) float64 {
+	var gains, losses float64
+	for _, r := range dailyPnLs {
+		if r > theta {
+			gains += r - theta
+		} else if r < theta {
+			losses += theta - r
+		}
+	}
+	if losses == 0 {
+		return 0
+	}
+	return gains / losses
+}
+
+// historicalVaR returns the loss (as a positive ISK figure) at the given
+// confidence level (e.g. 0.95) using the historical simulation method: sort
+// daily P&Ls ascending and take the value at the (1-confidence) percentile.
+func historicalVaR(dailyPnLs []float64, confidence float64) float64 {
+	if len(dailyPnLs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), dailyPnLs...)
+	sort.Float64s(sorted)
+
+	// The 1e-9 absorbs float error in 1-confidence (1-0.8 is
+	// 0.19999999999999996), which would otherwise drop the index by one.
+	idx := int(math.Floor((1-confidence)*float64(len(sorted)) + 1e-9))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	if sorted[idx] >= 0 {
+		return 0
+	}
+	return -sorted[idx]
+}
+
+// historicalCVaR is the average loss beyond the VaR cutoff (the mean of the
+// tail at or below the percentile), again returned as a positive ISK figure.
+func historicalCVaR(dailyPnLs []float64, confidence float64) float64 {
+	if len(dailyPnLs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), dailyPnLs...)
+	sort.Float64s(sorted)
+
+	idx := int(math.Floor((1-confidence)*float64(len(sorted)) + 1e-9)) // same cutoff as historicalVaR
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	tail := sorted[:idx+1]
+	var sum float64
+	for _, v := range tail {
+		sum += v
+	}
+	avg := sum / float64(len(tail))
+	if avg >= 0 {
+		return 0
+	}
+	return -avg
+}
+
+// ulcerIndex is sqrt(mean(drawdownPct^2)) over the daily drawdown series
+// (each entry already expressed as a negative or zero percentage).
+func ulcerIndex(drawdownPcts []float64) float64 {
+	if len(drawdownPcts) == 0 {
+		return 0
+	}
+	var sumSq float64
+	for _, d := range drawdownPcts {
+		sumSq += d * d
+	}
+	return math.Sqrt(sumSq / float64(len(drawdownPcts)))
+}
+
+// ulcerPerformanceIndex is annualized return divided by the Ulcer Index.
+// Zero-safe: returns 0 when the Ulcer Index is 0.
+func ulcerPerformanceIndex(annualizedReturnPct, ulcer float64) float64 {
+	if ulcer == 0 {
+		return 0
+	}
+	return annualizedReturnPct / ulcer
+}
+
+// cagrPercent computes (1 + totalPnL/startingCapital)^(365/totalDays) - 1,
+// expressed as a percentage. Zero-safe: returns 0 when startingCapital or
+// totalDays is non-positive, or when the base of the exponent is negative
+// (total loss exceeding starting capital).
+func cagrPercent(totalPnL, startingCapital float64, totalDays int) float64 {
+	if startingCapital <= 0 || totalDays <= 0 {
+		return 0
+	}
+	base := 1 + totalPnL/startingCapital
+	if base <= 0 {
+		return 0
+	}
+	return (math.Pow(base, 365.0/float64(totalDays)) - 1) * 100
+}
+
+// avgDrawdownPct is the mean depth of every discrete drawdown episode in
+// the cumulative P&L series, where an episode starts at a new equity peak
+// and ends when equity reclaims that peak (or at the series end).
+func avgDrawdownPct(cumulative []float64) float64 {
+	if len(cumulative) == 0 {
+		return 0
+	}
+	peak := cumulative[0]
+	inDrawdown := false
+	var troughDrawdown float64 // most negative % seen during the current episode
+	var episodes []float64
+
+	for _, c := range cumulative {
+		if c >= peak {
+			if inDrawdown {
+				episodes = append(episodes, troughDrawdown)
+				inDrawdown = false
+				troughDrawdown = 0
+			}
+			peak = c
+			continue
+		}
+		inDrawdown = true
+		var pct float64
+		if peak > 0 {
+			pct = (c - peak) / peak * 100
+		}
+		if pct < troughDrawdown {
+			troughDrawdown = pct
+		}
+	}
+	if inDrawdown {
+		episodes = append(episodes, troughDrawdown)
+	}
+	if len(episodes) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, e := range episodes {
+		sum += e
+	}
+	return sum / float64(len(episodes))
+}
+
+// kRatio measures the consistency of an equity curve's growth: the slope of
+// a linear regression of cumulative P&L on day index, divided by the
+// slope's standard error, scaled by sqrt(n). Zero-safe: returns 0 for
+// fewer than 3 points or a degenerate (zero-variance) day-index series.
+func kRatio(cumulative []float64) float64 {
+	n := len(cumulative)
+	if n < 3 {
+		return 0
+	}
+
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = float64(i)
+	}
+	xMean := mean(xs)
+	yMean := mean(cumulative)
+
+	var sumXY, sumXX float64
+	for i := 0; i < n; i++ {
+		dx := xs[i] - xMean
+		sumXY += dx * (cumulative[i] - yMean)
+		sumXX += dx * dx
+	}
+	if sumXX == 0 {
+		return 0
+	}
+	slope := sumXY / sumXX
+
+	var sumResidSq float64
+	for i := 0; i < n; i++ {
+		predicted := yMean + slope*(xs[i]-xMean)
+		resid := cumulative[i] - predicted
+		sumResidSq += resid * resid
+	}
+	if n <= 2 {
+		return 0
+	}
+	residualVariance := sumResidSq / float64(n-2)
+	stdErr := math.Sqrt(residualVariance / sumXX)
+	if stdErr == 0 {
+		return 0
+	}
+	return (slope / stdErr) * math.Sqrt(float64(n))
+}
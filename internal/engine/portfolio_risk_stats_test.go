@@ -0,0 +1,129 @@
+package engine
+
+import "testing"
+
+func approxEqual(a, b, tol float64) bool {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	return d <= tol
+}
+
+func TestSortinoRatio_ZeroSafeWithNoDownside(t *testing.T) {
+	if got := sortinoRatio([]float64{1, 2, 3}); got != 0 {
+		t.Fatalf("sortinoRatio with no losses = %v, want 0", got)
+	}
+}
+
+func TestSortinoRatio_HandComputed(t *testing.T) {
+	// mean = (2-1-1)/3 = 0; downside = [0,-1,-1] -> rms = sqrt((0+1+1)/3) = sqrt(2/3)
+	got := sortinoRatio([]float64{2, -1, -1})
+	if got != 0 { // mean is 0, so ratio is 0 regardless of downside dev
+		t.Fatalf("sortinoRatio = %v, want 0 (zero mean)", got)
+	}
+}
+
+func TestOmegaRatio_HandComputed(t *testing.T) {
+	// gains above 0: 2+3=5; losses below 0: 1+2=3 -> ratio 5/3
+	got := omegaRatio([]float64{2, 3, -1, -2}, 0)
+	want := 5.0 / 3.0
+	if !approxEqual(got, want, 1e-9) {
+		t.Fatalf("omegaRatio = %v, want %v", got, want)
+	}
+}
+
+func TestOmegaRatio_ZeroSafeNoLosses(t *testing.T) {
+	if got := omegaRatio([]float64{1, 2, 3}, 0); got != 0 {
+		t.Fatalf("omegaRatio with no losses = %v, want 0", got)
+	}
+}
+
+func TestHistoricalVaR_HandComputed(t *testing.T) {
+	// 10 values sorted: -100,-50,-20,-10,-5,0,5,10,20,50
+	pnls := []float64{0, 5, 10, 20, 50, -100, -50, -20, -10, -5}
+	got := historicalVaR(pnls, 0.95) // idx = floor(0.05*10) = 0 -> sorted[0] = -100
+	if got != 100 {
+		t.Fatalf("VaR95 = %v, want 100", got)
+	}
+}
+
+func TestHistoricalCVaR_HandComputed(t *testing.T) {
+	pnls := []float64{0, 5, 10, 20, 50, -100, -50, -20, -10, -5}
+	got := historicalCVaR(pnls, 0.80) // idx = floor(0.2*10) = 2 -> tail = [-100,-50,-20]
+	want := (100.0 + 50.0 + 20.0) / 3.0
+	if !approxEqual(got, want, 1e-9) {
+		t.Fatalf("CVaR80 = %v, want %v", got, want)
+	}
+}
+
+func TestUlcerIndex_HandComputed(t *testing.T) {
+	// drawdowns: 0, -10, -20 -> sqrt((0+100+400)/3) = sqrt(166.67) ~= 12.91
+	got := ulcerIndex([]float64{0, -10, -20})
+	want := 12.909944
+	if !approxEqual(got, want, 1e-4) {
+		t.Fatalf("ulcerIndex = %v, want %v", got, want)
+	}
+}
+
+func TestUlcerPerformanceIndex_ZeroSafe(t *testing.T) {
+	if got := ulcerPerformanceIndex(10, 0); got != 0 {
+		t.Fatalf("ulcerPerformanceIndex with 0 ulcer = %v, want 0", got)
+	}
+}
+
+func TestCAGRPercent_HandComputed(t *testing.T) {
+	// +10% over 365 days on 1,000,000 starting capital -> CAGR = 10%
+	got := cagrPercent(100_000, 1_000_000, 365)
+	if !approxEqual(got, 10, 1e-6) {
+		t.Fatalf("cagrPercent = %v, want 10", got)
+	}
+}
+
+func TestCAGRPercent_ZeroSafe(t *testing.T) {
+	if got := cagrPercent(100, 0, 100); got != 0 {
+		t.Fatalf("cagrPercent with 0 starting capital = %v, want 0", got)
+	}
+	if got := cagrPercent(100, 100, 0); got != 0 {
+		t.Fatalf("cagrPercent with 0 days = %v, want 0", got)
+	}
+}
+
+func TestAvgDrawdownPct_HandComputed(t *testing.T) {
+	// Equity: 100 (peak) -> 90 (-10%) -> 80 (-20%, trough) -> 100 (recovered, episode 1 = -20)
+	// -> 150 (new peak) -> 120 (-20%, trough) -> episode 2 = -20
+	cumulative := []float64{100, 90, 80, 100, 150, 120}
+	got := avgDrawdownPct(cumulative)
+	want := -20.0
+	if !approxEqual(got, want, 1e-6) {
+		t.Fatalf("avgDrawdownPct = %v, want %v", got, want)
+	}
+}
+
+func TestAvgDrawdownPct_NoDrawdowns(t *testing.T) {
+	if got := avgDrawdownPct([]float64{10, 20, 30}); got != 0 {
+		t.Fatalf("avgDrawdownPct with monotonic gains = %v, want 0", got)
+	}
+}
+
+func TestKRatio_PerfectLineIsZeroSafe(t *testing.T) {
+	// Perfectly linear growth has zero residual error, so stdErr is 0 and
+	// the function must stay zero-safe rather than divide by zero.
+	got := kRatio([]float64{0, 10, 20, 30, 40})
+	if got != 0 {
+		t.Fatalf("kRatio on a perfect line = %v, want 0 (zero-safe on zero stderr)", got)
+	}
+}
+
+func TestKRatio_ZeroSafeShortSeries(t *testing.T) {
+	if got := kRatio([]float64{1, 2}); got != 0 {
+		t.Fatalf("kRatio with < 3 points = %v, want 0", got)
+	}
+}
+
+func TestKRatio_NoisySeriesIsFinite(t *testing.T) {
+	got := kRatio([]float64{0, 12, 8, 25, 18, 40})
+	if got == 0 {
+		t.Fatal("expected a non-zero K-ratio for a noisy but trending series")
+	}
+}
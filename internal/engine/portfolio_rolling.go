@@ -0,0 +1,159 @@
+package engine
+
+import "math"
+
+// DefaultRollingWindowDays is the trailing window used for RollingSeries
+// when PortfolioPnLOptions.RollingWindowDays is unset.
+const DefaultRollingWindowDays = 30
+
+// MinRollingWindowDays and MaxRollingWindowDays bound
+// PortfolioPnLOptions.RollingWindowDays.
+const (
+	MinRollingWindowDays = 7
+	MaxRollingWindowDays = 90
+)
+
+// RollingSeries is a per-day time series of the same Sharpe/win-rate/
+// profit-factor/drawdown/ROI formulas used for PortfolioPnLStats, each
+// recomputed over a trailing WindowDays window ending on that day. Dates
+// mirrors PortfolioPnL.DailyPnL 1:1 so a front-end can overlay both on the
+// same equity chart. The first WindowDays-1 entries of every metric series
+// don't have a full window yet and are left nil, which encodes as JSON
+// null, rather than a misleading zero.
+type RollingSeries struct {
+	WindowDays     int        `json:"window_days"`
+	Dates          []string   `json:"dates"`
+	SharpeRatio    []*float64 `json:"sharpe_ratio"`
+	WinRatePct     []*float64 `json:"win_rate_pct"`
+	ProfitFactor   []*float64 `json:"profit_factor"`
+	MaxDrawdownPct []*float64 `json:"max_drawdown_pct"`
+	ROIPercent     []*float64 `json:"roi_percent"`
+}
+
+func normalizeRollingWindowDays(days int) int {
+	if days <= 0 {
+		days = DefaultRollingWindowDays
+	}
+	if days < MinRollingWindowDays {
+		days = MinRollingWindowDays
+	}
+	if days > MaxRollingWindowDays {
+		days = MaxRollingWindowDays
+	}
+	return days
+}
+
+// computeRollingSeries slides a windowDays window over days (already sorted
+// chronologically) and recomputes Sharpe, win rate, profit factor, max
+// drawdown, and ROI for each window, the same way the overall
+// PortfolioPnLStats summary computes them but scoped to the trailing
+// window ending on that day.
+func computeRollingSeries(days []DailyPnLEntry, windowDays int) RollingSeries {
+	out := RollingSeries{
+		WindowDays:     windowDays,
+		Dates:          make([]string, len(days)),
+		SharpeRatio:    make([]*float64, len(days)),
+		WinRatePct:     make([]*float64, len(days)),
+		ProfitFactor:   make([]*float64, len(days)),
+		MaxDrawdownPct: make([]*float64, len(days)),
+		ROIPercent:     make([]*float64, len(days)),
+	}
+
+	for i, d := range days {
+		out.Dates[i] = d.Date
+		if i+1 < windowDays {
+			continue // not enough history yet; leave nil (JSON null)
+		}
+		window := days[i+1-windowDays : i+1]
+
+		dailyPnLs := make([]float64, len(window))
+		for j, w := range window {
+			dailyPnLs[j] = w.NetPnL
+		}
+		sharpe := windowSharpe(dailyPnLs)
+		out.SharpeRatio[i] = &sharpe
+
+		winRate, profitFactor := windowWinRateAndProfitFactor(window)
+		out.WinRatePct[i] = &winRate
+		out.ProfitFactor[i] = &profitFactor
+
+		maxDD, windowPnL := windowMaxDrawdownPct(window)
+		out.MaxDrawdownPct[i] = &maxDD
+
+		roi := windowROIPercent(window, windowPnL)
+		out.ROIPercent[i] = &roi
+	}
+	return out
+}
+
+func windowSharpe(dailyPnLs []float64) float64 {
+	mu := mean(dailyPnLs)
+	sigma := math.Sqrt(variance(dailyPnLs))
+	if sigma == 0 {
+		return 0
+	}
+	return (mu / sigma) * math.Sqrt(365)
+}
+
+func windowWinRateAndProfitFactor(window []DailyPnLEntry) (winRatePct, profitFactor float64) {
+	var wins int
+	var grossProfit, grossLoss float64
+	for _, w := range window {
+		if w.NetPnL > 0 {
+			wins++
+			grossProfit += w.NetPnL
+		} else if w.NetPnL < 0 {
+			grossLoss += -w.NetPnL
+		}
+	}
+	winRatePct = float64(wins) / float64(len(window)) * 100
+	if grossLoss > 0 {
+		profitFactor = grossProfit / grossLoss
+	}
+	return winRatePct, profitFactor
+}
+
+// windowMaxDrawdownPct replays the window's own cumulative P&L from zero
+// (rather than the portfolio's running total) so the drawdown reflects
+// only what happened inside this window. Also returns the window's total
+// P&L so callers don't need to re-sum it for ROI.
+func windowMaxDrawdownPct(window []DailyPnLEntry) (maxDrawdownPct, totalPnL float64) {
+	var cumulative, peak float64
+	for _, w := range window {
+		cumulative += w.NetPnL
+		if cumulative > peak {
+			peak = cumulative
+		}
+		dd := cumulative - peak
+		if peak > 0 {
+			ddPct := dd / peak * 100
+			if ddPct < maxDrawdownPct {
+				maxDrawdownPct = ddPct
+			}
+		}
+	}
+	return maxDrawdownPct, cumulative
+}
+
+// windowROIPercent mirrors the summary's time-weighted average deployed
+// capital approach, scoped to the window.
+func windowROIPercent(window []DailyPnLEntry, windowPnL float64) float64 {
+	var cumBuy, cumSell, capitalSum, totalBought float64
+	for _, w := range window {
+		cumBuy += w.BuyTotal
+		cumSell += w.SellTotal
+		totalBought += w.BuyTotal
+		deployed := cumBuy - cumSell
+		if deployed > 0 {
+			capitalSum += deployed
+		}
+	}
+	avgCapital := capitalSum / float64(len(window))
+	if avgCapital > 0 {
+		return windowPnL / avgCapital * 100
+	}
+	if totalBought > 0 {
+		return windowPnL / totalBought * 100
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package engine
+
+import "testing"
+
+func TestNormalizeRollingWindowDays_DefaultAndClamp(t *testing.T) {
+	if got := normalizeRollingWindowDays(0); got != DefaultRollingWindowDays {
+		t.Errorf("normalizeRollingWindowDays(0) = %d, want default %d", got, DefaultRollingWindowDays)
+	}
+	if got := normalizeRollingWindowDays(1); got != MinRollingWindowDays {
+		t.Errorf("normalizeRollingWindowDays(1) = %d, want min %d", got, MinRollingWindowDays)
+	}
+	if got := normalizeRollingWindowDays(1000); got != MaxRollingWindowDays {
+		t.Errorf("normalizeRollingWindowDays(1000) = %d, want max %d", got, MaxRollingWindowDays)
+	}
+}
+
+func TestComputeRollingSeries_LeadingEntriesAreNil(t *testing.T) {
+	days := make([]DailyPnLEntry, 5)
+	for i := range days {
+		days[i] = DailyPnLEntry{Date: "d", NetPnL: 10}
+	}
+	series := computeRollingSeries(days, 7)
+	for i, v := range series.SharpeRatio {
+		if v != nil {
+			t.Fatalf("SharpeRatio[%d] = %v, want nil (only %d days of history)", i, *v, len(days))
+		}
+	}
+}
+
+func TestComputeRollingSeries_DatesAlignWithInput(t *testing.T) {
+	days := []DailyPnLEntry{{Date: "2024-01-01"}, {Date: "2024-01-02"}, {Date: "2024-01-03"}}
+	series := computeRollingSeries(days, 7)
+	for i, d := range days {
+		if series.Dates[i] != d.Date {
+			t.Fatalf("Dates[%d] = %q, want %q", i, series.Dates[i], d.Date)
+		}
+	}
+}
+
+func TestComputeRollingSeries_FullWindowIsPopulated(t *testing.T) {
+	days := []DailyPnLEntry{
+		{Date: "1", NetPnL: 10, BuyTotal: 100, SellTotal: 110},
+		{Date: "2", NetPnL: -5, BuyTotal: 100, SellTotal: 95},
+		{Date: "3", NetPnL: 20, BuyTotal: 100, SellTotal: 120},
+	}
+	series := computeRollingSeries(days, 3)
+	if series.WinRatePct[2] == nil {
+		t.Fatal("WinRatePct[2] = nil, want populated once the window is full")
+	}
+	// 2 of 3 days profitable, computed in float64 the same way as the code
+	// (an untyped constant 2.0/3.0*100 rounds differently in the last bit).
+	winDays, windowDays := 2.0, 3.0
+	want := winDays / windowDays * 100
+	if *series.WinRatePct[2] != want {
+		t.Fatalf("WinRatePct[2] = %v, want %v", *series.WinRatePct[2], want)
+	}
+}
+
+func TestWindowMaxDrawdownPct_ReplaysFromWindowStart(t *testing.T) {
+	window := []DailyPnLEntry{
+		{NetPnL: 100}, // peak 100
+		{NetPnL: -50}, // cumulative 50, drawdown -50%
+	}
+	maxDD, totalPnL := windowMaxDrawdownPct(window)
+	if maxDD != -50 {
+		t.Errorf("maxDD = %v, want -50", maxDD)
+	}
+	if totalPnL != 50 {
+		t.Errorf("totalPnL = %v, want 50", totalPnL)
+	}
+}
@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// DefaultSimulationRuns is the number of bootstrap paths SimulatePortfolioPnL
+// draws when SimulationOptions.Runs is unset.
+const DefaultSimulationRuns = 1000
+
+// maxSimulationEnvelopePoints caps how many per-day bands the chartable
+// envelope carries; longer horizons are downsampled to this many points.
+const maxSimulationEnvelopePoints = 180
+
+// SimulationOptions controls the Monte-Carlo bootstrap run by
+// SimulatePortfolioPnL.
+type SimulationOptions struct {
+	// Runs is the number of bootstrap paths to simulate. Defaults to
+	// DefaultSimulationRuns.
+	Runs int
+	// HorizonDays is the length of each synthetic equity curve. Defaults to
+	// the PortfolioPnLOptions.LookbackDays used to compute the realized P&L.
+	HorizonDays int
+	// BlockLen is the mean length of each resampled block of daily P&L, used
+	// to preserve day-to-day autocorrelation (stationary block bootstrap).
+	// Defaults to round(sqrt(n)) where n is the number of realized daily
+	// P&L samples.
+	BlockLen int
+	// Seed seeds the deterministic PRNG so repeated calls with the same
+	// inputs reproduce the same bands.
+	Seed int64
+}
+
+// SimulatedMetricStats is the distribution of one terminal metric across
+// all bootstrap runs.
+type SimulatedMetricStats struct {
+	Mean float64 `json:"mean"`
+	P5   float64 `json:"p5"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P95  float64 `json:"p95"`
+}
+
+// SimulatedDayBand is one day's median and 5/95 percentile envelope of
+// cumulative P&L across all bootstrap runs.
+type SimulatedDayBand struct {
+	Day    int     `json:"day"`
+	Median float64 `json:"median"`
+	P5     float64 `json:"p5"`
+	P95    float64 `json:"p95"`
+}
+
+// PortfolioSimulation is the Monte-Carlo bootstrap result returned by
+// SimulatePortfolioPnL: a "what could next month look like" distribution
+// built by stationary block-bootstrapping the trader's own realized daily
+// P&L series, not an external statistical model.
+type PortfolioSimulation struct {
+	Runs           int                  `json:"runs"`
+	HorizonDays    int                  `json:"horizon_days"`
+	BlockLen       int                  `json:"block_len"`
+	Seed           int64                `json:"seed"`
+	TerminalPnL    SimulatedMetricStats `json:"terminal_pnl"`
+	MaxDrawdownPct SimulatedMetricStats `json:"max_drawdown_pct"`
+	SharpeRatio    SimulatedMetricStats `json:"sharpe_ratio"`
+	CalmarRatio    SimulatedMetricStats `json:"calmar_ratio"`
+	Envelope       []SimulatedDayBand   `json:"envelope"`
+}
+
+func normalizeSimulationOptions(sim SimulationOptions, lookbackDays, numDailyPnLs int) SimulationOptions {
+	if sim.Runs <= 0 {
+		sim.Runs = DefaultSimulationRuns
+	}
+	if sim.HorizonDays <= 0 {
+		sim.HorizonDays = lookbackDays
+	}
+	if sim.HorizonDays <= 0 {
+		sim.HorizonDays = 30
+	}
+	if sim.BlockLen <= 0 {
+		sim.BlockLen = int(math.Round(math.Sqrt(float64(numDailyPnLs))))
+	}
+	if sim.BlockLen < 1 {
+		sim.BlockLen = 1
+	}
+	return sim
+}
+
+// SimulatePortfolioPnL runs a stationary block-bootstrap Monte-Carlo
+// simulation over the realized daily P&L series from
+// ComputePortfolioPnLWithOptions, giving confidence bands for terminal
+// P&L, max drawdown, Sharpe, and Calmar under the trader's own realized
+// return distribution. Each run resamples blocks of daily P&L of geometric
+// random length (mean sim.BlockLen) with replacement (wrapping circularly)
+// to build a synthetic equity curve of length sim.HorizonDays. Results are
+// deterministic for a given sim.Seed.
+func SimulatePortfolioPnL(txns []esi.WalletTransaction, opt PortfolioPnLOptions, sim SimulationOptions) *PortfolioSimulation {
+	pnl := ComputePortfolioPnLWithOptions(txns, opt)
+	dailyPnLs := make([]float64, len(pnl.DailyPnL))
+	for i, d := range pnl.DailyPnL {
+		dailyPnLs[i] = d.NetPnL
+	}
+
+	opt = normalizePortfolioOptions(opt)
+	sim = normalizeSimulationOptions(sim, opt.LookbackDays, len(dailyPnLs))
+	out := &PortfolioSimulation{
+		Runs:        sim.Runs,
+		HorizonDays: sim.HorizonDays,
+		BlockLen:    sim.BlockLen,
+		Seed:        sim.Seed,
+		Envelope:    []SimulatedDayBand{},
+	}
+	if len(dailyPnLs) == 0 {
+		return out
+	}
+
+	rng := rand.New(rand.NewSource(sim.Seed))
+
+	terminalPnLs := make([]float64, sim.Runs)
+	maxDrawdownPcts := make([]float64, sim.Runs)
+	sharpes := make([]float64, sim.Runs)
+	calmars := make([]float64, sim.Runs)
+	paths := make([][]float64, sim.Runs)
+
+	for r := 0; r < sim.Runs; r++ {
+		path := bootstrapBlockPath(dailyPnLs, sim.HorizonDays, sim.BlockLen, rng)
+
+		cumulative := make([]float64, sim.HorizonDays)
+		var cum, peak, maxDDISK, maxDDPct float64
+		for i, ret := range path {
+			cum += ret
+			cumulative[i] = cum
+			if cum > peak {
+				peak = cum
+			}
+			dd := cum - peak
+			if dd < maxDDISK {
+				maxDDISK = dd
+				if peak > 0 {
+					maxDDPct = dd / peak * 100
+				}
+			}
+		}
+		paths[r] = cumulative
+
+		mu := mean(path)
+		sigma := math.Sqrt(variance(path))
+		sharpe := 0.0
+		if sigma > 0 {
+			sharpe = mu / sigma * math.Sqrt(365)
+		}
+
+		annualizedReturn := cum * 365 / float64(sim.HorizonDays)
+		calmar := 0.0
+		if maxDDISK < 0 {
+			calmar = annualizedReturn / -maxDDISK
+		}
+
+		terminalPnLs[r] = cum
+		maxDrawdownPcts[r] = maxDDPct
+		sharpes[r] = sharpe
+		calmars[r] = calmar
+	}
+
+	out.TerminalPnL = summarizeSimulatedMetric(terminalPnLs)
+	out.MaxDrawdownPct = summarizeSimulatedMetric(maxDrawdownPcts)
+	out.SharpeRatio = summarizeSimulatedMetric(sharpes)
+	out.CalmarRatio = summarizeSimulatedMetric(calmars)
+	out.Envelope = buildSimulationEnvelope(paths, sim.HorizonDays)
+
+	return out
+}
+
+// bootstrapBlockPath draws a synthetic return path of length horizonDays with
+// the stationary block bootstrap: each block starts at a random index into
+// returns and copies consecutive samples, wrapping circularly past the end
+// of returns, and after each sample a new block starts with probability
+// 1/blockLen, so block lengths are geometric with mean blockLen. Blocks
+// preserve local autocorrelation better than resampling single days
+// independently.
+func bootstrapBlockPath(returns []float64, horizonDays, blockLen int, rng *rand.Rand) []float64 {
+	n := len(returns)
+	newBlockProb := 1 / float64(blockLen)
+	path := make([]float64, 0, horizonDays)
+	idx := rng.Intn(n)
+	for len(path) < horizonDays {
+		path = append(path, returns[idx])
+		if rng.Float64() < newBlockProb {
+			idx = rng.Intn(n)
+		} else {
+			idx = (idx + 1) % n
+		}
+	}
+	return path
+}
+
+// summarizeSimulatedMetric reduces one metric's values across all bootstrap
+// runs to its mean plus 5/25/50/75/95 percentiles.
+func summarizeSimulatedMetric(values []float64) SimulatedMetricStats {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return SimulatedMetricStats{
+		Mean: mean(values),
+		P5:   percentileOf(sorted, 0.05),
+		P25:  percentileOf(sorted, 0.25),
+		P50:  percentileOf(sorted, 0.50),
+		P75:  percentileOf(sorted, 0.75),
+		P95:  percentileOf(sorted, 0.95),
+	}
+}
+
+// percentileOf returns the value at percentile p (0-1) of an
+// already-ascending-sorted slice: the element at index floor(p*(n-1)), so
+// p=0 and p=1 pick the first and last elements.
+func percentileOf(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Floor(p * float64(len(sorted)-1)))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// buildSimulationEnvelope computes the per-day median and 5/95 percentile
+// band of cumulative P&L across all bootstrap paths, downsampled to at most
+// maxSimulationEnvelopePoints days so long horizons stay cheap to chart.
+func buildSimulationEnvelope(paths [][]float64, horizonDays int) []SimulatedDayBand {
+	if len(paths) == 0 || horizonDays == 0 {
+		return []SimulatedDayBand{}
+	}
+	step := 1
+	if horizonDays > maxSimulationEnvelopePoints {
+		step = int(math.Ceil(float64(horizonDays) / float64(maxSimulationEnvelopePoints)))
+	}
+
+	envelope := make([]SimulatedDayBand, 0, horizonDays/step+1)
+	vals := make([]float64, len(paths))
+	for day := 0; day < horizonDays; day += step {
+		for r, path := range paths {
+			vals[r] = path[day]
+		}
+		sorted := append([]float64(nil), vals...)
+		sort.Float64s(sorted)
+		envelope = append(envelope, SimulatedDayBand{
+			Day:    day + 1,
+			Median: percentileOf(sorted, 0.50),
+			P5:     percentileOf(sorted, 0.05),
+			P95:    percentileOf(sorted, 0.95),
+		})
+	}
+	return envelope
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestNormalizeSimulationOptions_Defaults(t *testing.T) {
+	got := normalizeSimulationOptions(SimulationOptions{}, 30, 9)
+	if got.Runs != DefaultSimulationRuns {
+		t.Errorf("Runs = %d, want %d", got.Runs, DefaultSimulationRuns)
+	}
+	if got.HorizonDays != 30 {
+		t.Errorf("HorizonDays = %d, want 30 (lookback)", got.HorizonDays)
+	}
+	if got.BlockLen != 3 {
+		t.Errorf("BlockLen = %d, want 3 (round(sqrt(9)))", got.BlockLen)
+	}
+}
+
+func TestNormalizeSimulationOptions_ExplicitValuesKept(t *testing.T) {
+	got := normalizeSimulationOptions(SimulationOptions{Runs: 50, HorizonDays: 10, BlockLen: 2}, 30, 9)
+	if got.Runs != 50 || got.HorizonDays != 10 || got.BlockLen != 2 {
+		t.Fatalf("explicit options were overwritten: %+v", got)
+	}
+}
+
+func TestBootstrapBlockPath_LengthMatchesHorizon(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	path := bootstrapBlockPath([]float64{1, 2, 3, 4, 5}, 17, 4, rng)
+	if len(path) != 17 {
+		t.Fatalf("len(path) = %d, want 17", len(path))
+	}
+}
+
+func TestBootstrapBlockPath_OnlyDrawsFromSource(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	source := []float64{10, 20, 30}
+	path := bootstrapBlockPath(source, 25, 2, rng)
+	for _, v := range path {
+		found := false
+		for _, s := range source {
+			if v == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("path contains value %v not present in source %v", v, source)
+		}
+	}
+}
+
+func TestPercentileOf_Bounds(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+	if got := percentileOf(sorted, 0); got != 1 {
+		t.Errorf("p0 = %v, want 1", got)
+	}
+	if got := percentileOf(sorted, 1); got != 5 {
+		t.Errorf("p100 = %v, want 5", got)
+	}
+	if got := percentileOf(nil, 0.5); got != 0 {
+		t.Errorf("percentileOf(nil) = %v, want 0", got)
+	}
+}
+
+func TestSimulatePortfolioPnL_EmptyTxnsIsZeroSafe(t *testing.T) {
+	got := SimulatePortfolioPnL(nil, PortfolioPnLOptions{}, SimulationOptions{Runs: 10, Seed: 1})
+	if len(got.Envelope) != 0 {
+		t.Fatalf("Envelope = %v, want empty with no transactions", got.Envelope)
+	}
+	if got.TerminalPnL != (SimulatedMetricStats{}) {
+		t.Fatalf("TerminalPnL = %+v, want zero value", got.TerminalPnL)
+	}
+}
+
+func TestSimulatePortfolioPnL_DeterministicForSameSeed(t *testing.T) {
+	txns := simulationFixtureTxns()
+	opt := PortfolioPnLOptions{LookbackDays: 30, IncludeUnmatchedSell: true}
+	sim := SimulationOptions{Runs: 200, HorizonDays: 20, BlockLen: 2, Seed: 42}
+
+	first := SimulatePortfolioPnL(txns, opt, sim)
+	second := SimulatePortfolioPnL(txns, opt, sim)
+
+	if first.TerminalPnL != second.TerminalPnL {
+		t.Fatalf("TerminalPnL differs across runs with the same seed: %+v vs %+v", first.TerminalPnL, second.TerminalPnL)
+	}
+	if len(first.Envelope) != len(second.Envelope) {
+		t.Fatalf("Envelope length differs across runs with the same seed")
+	}
+}
+
+func TestSimulatePortfolioPnL_EnvelopeIsDownsampled(t *testing.T) {
+	txns := simulationFixtureTxns()
+	opt := PortfolioPnLOptions{LookbackDays: 30, IncludeUnmatchedSell: true}
+	got := SimulatePortfolioPnL(txns, opt, SimulationOptions{Runs: 20, HorizonDays: 400, BlockLen: 5, Seed: 7})
+
+	if len(got.Envelope) > maxSimulationEnvelopePoints+1 {
+		t.Fatalf("Envelope has %d points, want <= %d", len(got.Envelope), maxSimulationEnvelopePoints+1)
+	}
+}
+
+// simulationFixtureTxns builds a small buy/sell history across a handful of
+// days so ComputePortfolioPnLWithOptions produces a non-trivial daily P&L
+// series to bootstrap from.
+func simulationFixtureTxns() []esi.WalletTransaction {
+	base := time.Now().UTC().AddDate(0, 0, -10)
+	txns := []esi.WalletTransaction{}
+	for i := 0; i < 5; i++ {
+		buyDate := base.AddDate(0, 0, i)
+		sellDate := buyDate.Add(2 * time.Hour)
+		txns = append(txns,
+			esi.WalletTransaction{
+				TransactionID: int64(i*2 + 1),
+				Date:          buyDate.Format(time.RFC3339),
+				TypeID:        34,
+				TypeName:      "Tritanium",
+				IsBuy:         true,
+				Quantity:      100,
+				UnitPrice:     5,
+				LocationID:    60003760,
+				LocationName:  "Jita IV - Moon 4",
+			},
+			esi.WalletTransaction{
+				TransactionID: int64(i*2 + 2),
+				Date:          sellDate.Format(time.RFC3339),
+				TypeID:        34,
+				TypeName:      "Tritanium",
+				IsBuy:         false,
+				Quantity:      100,
+				UnitPrice:     6 + float64(i%3),
+				LocationID:    60003760,
+				LocationName:  "Jita IV - Moon 4",
+			},
+		)
+	}
+	return txns
+}
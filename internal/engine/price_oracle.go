@@ -0,0 +1,54 @@
+package engine
+
+// PriceOracle supplies a live market bid price for a type in a region, used
+// to mark open positions to market for unrealized P&L. The bool return is
+// false when no price is available (unknown region, no buy orders, etc.);
+// callers should degrade to zero unrealized P&L rather than treating it as
+// an error.
+type PriceOracle interface {
+	Bid(typeID int32, regionID int64) (float64, bool)
+}
+
+// oraclePriceKey identifies one PriceOracle.Bid lookup.
+type oraclePriceKey struct {
+	typeID   int32
+	regionID int64
+}
+
+// oraclePriceCache memoizes PriceOracle.Bid lookups for the lifetime of a
+// single ComputePortfolioPnLWithOptions call, so a portfolio with many open
+// lots of the same type/region costs at most one oracle lookup per pair.
+// A nil oracle degrades every lookup to (0, false) rather than panicking.
+type oraclePriceCache struct {
+	oracle PriceOracle
+	bids   map[oraclePriceKey]struct {
+		price float64
+		ok    bool
+	}
+}
+
+func newOraclePriceCache(oracle PriceOracle) *oraclePriceCache {
+	return &oraclePriceCache{
+		oracle: oracle,
+		bids: make(map[oraclePriceKey]struct {
+			price float64
+			ok    bool
+		}),
+	}
+}
+
+func (c *oraclePriceCache) bid(typeID int32, regionID int64) (float64, bool) {
+	if c.oracle == nil {
+		return 0, false
+	}
+	key := oraclePriceKey{typeID: typeID, regionID: regionID}
+	if cached, ok := c.bids[key]; ok {
+		return cached.price, cached.ok
+	}
+	price, ok := c.oracle.Bid(typeID, regionID)
+	c.bids[key] = struct {
+		price float64
+		ok    bool
+	}{price: price, ok: ok}
+	return price, ok
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// ItemPlacement records one contract item's sell-side placement price -
+// where it would realistically need to be listed given the depth already
+// sitting ahead of it in the order book - so users aren't just shown the
+// naive top-of-book price ScanContracts valued it against.
+type ItemPlacement struct {
+	TypeID         int32   `json:"type_id"`
+	Quantity       int32   `json:"quantity"`
+	PlacementPrice float64 `json:"placement_price"`
+}
+
+// SellPlan is the result of walking the sell side of the order book to
+// find where a new listing of qty units would realistically need to be
+// priced, instead of valuing it at the untouched top of book.
+type SellPlan struct {
+	CanPrice bool
+	// PlacementPrice is the untouched top of book discounted by however far
+	// the walk had to climb to find matching depth: a listing that needs to
+	// out-compete depth sitting deep-minus-top ISK above the top of book has
+	// to undercut by that same distance below it, not match the deeper
+	// price itself.
+	PlacementPrice float64
+	// AvgProceeds is the effective per-unit proceeds from listing at
+	// PlacementPrice. Unlike the buy-side depth walk (ComputeExecutionPlan),
+	// a sell listing clears at one price rather than sweeping several
+	// book levels, so this is simply PlacementPrice - kept as its own
+	// field for symmetry with ComputeExecutionPlan's ExpectedPrice.
+	AvgProceeds float64
+}
+
+// ComputeSellPlacementPlan walks sellOrders (ascending price) to estimate
+// where a new qty-unit listing would realistically need to sit: existing
+// sellers with combined volume greater than qty/participation are
+// effectively "ahead" of it in the queue, so the listing can't expect to
+// out-wait them within the scan's hold horizon and has to price itself
+// competitively instead. The further up the ascending book that depth
+// reaches, the more this listing needs to undercut the untouched top of
+// book to actually move qty units - so the walk's distance above the top
+// is mirrored back below it, not matched. participation mirrors
+// ContractFillParticipation's role on the fill side - the lower it is
+// (or the thinner the book), the deeper the walk and the lower the
+// resulting price. Returns CanPrice=false if there's no sell-side depth
+// to walk, or if the discount would push the price to zero or below.
+func ComputeSellPlacementPlan(sellOrders []esi.MarketOrder, qty int32, participation float64) SellPlan {
+	if len(sellOrders) == 0 {
+		return SellPlan{}
+	}
+	if participation <= 0 {
+		participation = ContractFillParticipation
+	}
+	if qty <= 0 {
+		return SellPlan{CanPrice: true}
+	}
+
+	sorted := make([]esi.MarketOrder, len(sellOrders))
+	copy(sorted, sellOrders)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	threshold := float64(qty) / participation
+
+	var cumVolume, topOfBook, deepest float64
+	for _, o := range sorted {
+		if o.Price <= 0 || o.VolumeRemain <= 0 {
+			continue
+		}
+		if topOfBook <= 0 {
+			topOfBook = o.Price
+		}
+		deepest = o.Price
+		cumVolume += float64(o.VolumeRemain)
+		if cumVolume >= threshold {
+			break
+		}
+	}
+	if topOfBook <= 0 {
+		return SellPlan{}
+	}
+
+	placement := topOfBook - (deepest - topOfBook)
+	if placement <= 0 {
+		return SellPlan{}
+	}
+
+	return SellPlan{CanPrice: true, PlacementPrice: placement, AvgProceeds: placement}
+}
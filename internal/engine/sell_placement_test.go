@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestComputeSellPlacementPlan_WalksPastThinTopOfBook(t *testing.T) {
+	// Top of book is thin (10 units at 100); selling 100 units needs to
+	// undercut below the untouched top, not match the deeper, pricier
+	// competition it has to out-compete.
+	orders := []esi.MarketOrder{
+		{Price: 100, VolumeRemain: 10},
+		{Price: 110, VolumeRemain: 50},
+		{Price: 120, VolumeRemain: 100},
+	}
+	plan := ComputeSellPlacementPlan(orders, 100, 0.5) // threshold = 100/0.5 = 200
+	if !plan.CanPrice {
+		t.Fatalf("expected CanPrice=true")
+	}
+	// Cumulative volume: 10 (@100) -> 60 (@110) -> 160 (@120), still < 200,
+	// so the walk reaches as deep as 120 - 20 above the top of book (100) -
+	// and that distance is mirrored back below the top: 100 - 20 = 80.
+	if plan.PlacementPrice != 80 {
+		t.Errorf("PlacementPrice = %v, want 80", plan.PlacementPrice)
+	}
+	if plan.AvgProceeds != plan.PlacementPrice {
+		t.Errorf("AvgProceeds = %v, want PlacementPrice %v", plan.AvgProceeds, plan.PlacementPrice)
+	}
+}
+
+func TestComputeSellPlacementPlan_DeepBookStopsEarly(t *testing.T) {
+	orders := []esi.MarketOrder{
+		{Price: 100, VolumeRemain: 1000},
+		{Price: 110, VolumeRemain: 1000},
+	}
+	plan := ComputeSellPlacementPlan(orders, 100, 0.5) // threshold = 200, crossed within the first order
+	if !plan.CanPrice {
+		t.Fatalf("expected CanPrice=true")
+	}
+	if plan.PlacementPrice != 100 {
+		t.Errorf("PlacementPrice = %v, want 100 (threshold crossed within the cheapest order)", plan.PlacementPrice)
+	}
+}
+
+func TestComputeSellPlacementPlan_NoBookDepth(t *testing.T) {
+	if plan := ComputeSellPlacementPlan(nil, 10, 0.5); plan.CanPrice {
+		t.Errorf("expected CanPrice=false with no sell orders, got %+v", plan)
+	}
+}
+
+func TestComputeSellPlacementPlan_ZeroQuantity(t *testing.T) {
+	orders := []esi.MarketOrder{{Price: 100, VolumeRemain: 10}}
+	plan := ComputeSellPlacementPlan(orders, 0, 0.5)
+	if !plan.CanPrice || plan.PlacementPrice != 0 {
+		t.Errorf("plan = %+v, want CanPrice=true with a zero PlacementPrice for zero quantity", plan)
+	}
+}
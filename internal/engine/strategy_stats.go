@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"math"
+)
+
+// StrategyStats is the aggregated risk/return summary across one or more
+// historical scans, computed from realized profit rows in flip_results,
+// contract_results, and station_results.
+type StrategyStats struct {
+	ScanIDs        []int64 `json:"scan_ids"`
+	Samples        int     `json:"samples"`
+	TotalProfit    float64 `json:"total_profit"`
+	SharpeRatio    float64 `json:"sharpe_ratio"`
+	SortinoRatio   float64 `json:"sortino_ratio"`
+	ProfitFactor   float64 `json:"profit_factor"`
+	WinningRatio   float64 `json:"winning_ratio"` // 0-100%
+	MaxDrawdown    float64 `json:"max_drawdown"`  // deepest peak-to-trough decline in ISK
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	CAGR           float64 `json:"cagr"` // 0-100%
+}
+
+// DefaultStrategyStatsPeriodsPerYear annualizes Sharpe/Sortino assuming one
+// return sample per scan and scans roughly once per trading day.
+const DefaultStrategyStatsPeriodsPerYear = 365.0
+
+// ComputeStrategyStats turns a series of realized profit samples (one per
+// scan or one per result row, caller's choice) into a StrategyStats summary.
+// Samples must be in chronological order; they are treated as the return
+// series for Sharpe/Sortino/drawdown/CAGR purposes.
+func ComputeStrategyStats(scanIDs []int64, profits []float64) StrategyStats {
+	out := StrategyStats{ScanIDs: scanIDs, Samples: len(profits)}
+	if len(profits) == 0 {
+		return out
+	}
+
+	for _, p := range profits {
+		out.TotalProfit += p
+	}
+
+	mu := mean(profits)
+	sigma := math.Sqrt(variance(profits))
+	if sigma > 0 {
+		out.SharpeRatio = mu / sigma * math.Sqrt(DefaultStrategyStatsPeriodsPerYear)
+	}
+
+	var downside []float64
+	for _, p := range profits {
+		if p < 0 {
+			downside = append(downside, p)
+		} else {
+			downside = append(downside, 0)
+		}
+	}
+	downsideDev := math.Sqrt(meanOfSquares(downside))
+	if downsideDev > 0 {
+		out.SortinoRatio = mu / downsideDev * math.Sqrt(DefaultStrategyStatsPeriodsPerYear)
+	}
+
+	var grossProfit, grossLoss float64
+	var wins int
+	for _, p := range profits {
+		if p > 0 {
+			grossProfit += p
+			wins++
+		} else if p < 0 {
+			grossLoss += -p
+		}
+	}
+	if grossLoss > 0 {
+		out.ProfitFactor = grossProfit / grossLoss
+	}
+	out.WinningRatio = float64(wins) / float64(len(profits)) * 100
+
+	// Equity curve / drawdown.
+	cumulative := 0.0
+	peak := 0.0
+	maxDD := 0.0
+	for _, p := range profits {
+		cumulative += p
+		if cumulative > peak {
+			peak = cumulative
+		}
+		dd := peak - cumulative
+		if dd > maxDD {
+			maxDD = dd
+		}
+	}
+	out.MaxDrawdown = maxDD
+	if peak > 0 {
+		out.MaxDrawdownPct = maxDD / peak * 100
+	}
+
+	// CAGR assumes one sample per day and a starting capital equal to the
+	// largest drawdown observed, or to the absolute value of the first sample
+	// when there was no drawdown. A more precise figure has to be computed
+	// from the equity curve directly.
+	startingCapital := maxDD
+	if startingCapital <= 0 {
+		startingCapital = math.Abs(profits[0])
+	}
+	if startingCapital > 0 && len(profits) > 0 {
+		years := float64(len(profits)) / DefaultStrategyStatsPeriodsPerYear
+		if years > 0 {
+			growth := 1 + out.TotalProfit/startingCapital
+			if growth > 0 {
+				out.CAGR = (math.Pow(growth, 1/years) - 1) * 100
+			}
+		}
+	}
+
+	return out
+}
+
+func meanOfSquares(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum / float64(len(x))
+}
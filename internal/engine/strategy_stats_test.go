@@ -0,0 +1,43 @@
+package engine
+
+import "testing"
+
+func TestComputeStrategyStats_Empty(t *testing.T) {
+	stats := ComputeStrategyStats(nil, nil)
+	if stats.Samples != 0 || stats.SharpeRatio != 0 {
+		t.Fatalf("empty stats = %+v, want zero value", stats)
+	}
+}
+
+func TestComputeStrategyStats_AllWins(t *testing.T) {
+	profits := []float64{100, 200, 150, 300}
+	stats := ComputeStrategyStats([]int64{1, 2, 3, 4}, profits)
+
+	if stats.WinningRatio != 100 {
+		t.Errorf("WinningRatio = %v, want 100", stats.WinningRatio)
+	}
+	if stats.ProfitFactor != 0 {
+		t.Errorf("ProfitFactor with no losses = %v, want 0 (no division by zero)", stats.ProfitFactor)
+	}
+	if stats.MaxDrawdown != 0 {
+		t.Errorf("MaxDrawdown with monotonic gains = %v, want 0", stats.MaxDrawdown)
+	}
+	if stats.TotalProfit != 750 {
+		t.Errorf("TotalProfit = %v, want 750", stats.TotalProfit)
+	}
+}
+
+func TestComputeStrategyStats_MixedWithDrawdown(t *testing.T) {
+	profits := []float64{100, -50, 30, -80, 40}
+	stats := ComputeStrategyStats([]int64{1, 2, 3, 4, 5}, profits)
+
+	if stats.WinningRatio != 60 {
+		t.Errorf("WinningRatio = %v, want 60", stats.WinningRatio)
+	}
+	if stats.ProfitFactor <= 0 {
+		t.Errorf("ProfitFactor = %v, want > 0", stats.ProfitFactor)
+	}
+	if stats.MaxDrawdown <= 0 {
+		t.Errorf("MaxDrawdown = %v, want > 0", stats.MaxDrawdown)
+	}
+}
@@ -0,0 +1,123 @@
+package engine
+
+import "sync"
+
+// TrailingTier pairs an activation ratio (how far above entry price must
+// move before this tier arms) with a callback rate (how far price must
+// retrace from the tier's high-water mark before it fires a sell signal).
+// Modeled on bbgo's trailing-stop strategy config.
+type TrailingTier struct {
+	ActivationRatio float64 // e.g. 0.005 for 0.5% above entry
+	CallbackRate    float64 // e.g. 0.002 for 0.2% retracement from the high
+}
+
+// DefaultTrailingTiers is a reasonable starter ladder: tighten the callback
+// as price runs further above entry.
+var DefaultTrailingTiers = []TrailingTier{
+	{ActivationRatio: 0.005, CallbackRate: 0.002},
+	{ActivationRatio: 0.01, CallbackRate: 0.001},
+	{ActivationRatio: 0.02, CallbackRate: 0.0005},
+}
+
+// TrackedPosition is an entered flip/station-trade candidate being watched
+// for a trailing take-profit exit.
+type TrackedPosition struct {
+	ID            int64
+	TypeID        int32
+	TypeName      string
+	EntryPrice    float64
+	Quantity      int64
+	Tiers         []TrailingTier
+	ActiveTier    int     // index into Tiers of the highest tier armed so far, -1 if none armed
+	HighWaterMark float64 // highest observed price since entry (or since the active tier armed)
+	Closed        bool
+}
+
+// NewTrackedPosition starts a position at entry price with no tier armed
+// yet, falling back to DefaultTrailingTiers if none are given.
+func NewTrackedPosition(id int64, typeID int32, typeName string, entryPrice float64, quantity int64, tiers []TrailingTier) *TrackedPosition {
+	if len(tiers) == 0 {
+		tiers = DefaultTrailingTiers
+	}
+	return &TrackedPosition{
+		ID:            id,
+		TypeID:        typeID,
+		TypeName:      typeName,
+		EntryPrice:    entryPrice,
+		Quantity:      quantity,
+		Tiers:         tiers,
+		ActiveTier:    -1,
+		HighWaterMark: entryPrice,
+	}
+}
+
+// OnPriceUpdate feeds a live best-bid price into the position, promotes to
+// the next armed tier when price crosses its activation ratio above entry,
+// and reports whether the position should be sold now because price
+// retraced from the high-water mark by the active tier's callback rate.
+func (p *TrackedPosition) OnPriceUpdate(price float64) (sellNow bool) {
+	if p.Closed || p.EntryPrice <= 0 {
+		return false
+	}
+
+	if price > p.HighWaterMark {
+		p.HighWaterMark = price
+	}
+
+	// Promote to the furthest tier whose activation ratio has been reached.
+	for i := len(p.Tiers) - 1; i > p.ActiveTier; i-- {
+		threshold := p.EntryPrice * (1 + p.Tiers[i].ActivationRatio)
+		if price >= threshold {
+			p.ActiveTier = i
+			break
+		}
+	}
+
+	if p.ActiveTier < 0 {
+		return false
+	}
+
+	tier := p.Tiers[p.ActiveTier]
+	retracement := (p.HighWaterMark - price) / p.HighWaterMark
+	return retracement >= tier.CallbackRate
+}
+
+// TrailingTracker keeps the in-memory set of positions currently being
+// watched for a trailing take-profit exit, keyed by position ID. It starts
+// empty and loads nothing itself; the persisted copies of open positions,
+// with their last tier and high-water mark, are read back by
+// db.ListOpenPositions.
+type TrailingTracker struct {
+	mu        sync.Mutex
+	positions map[int64]*TrackedPosition
+}
+
+// NewTrailingTracker creates an empty tracker.
+func NewTrailingTracker() *TrailingTracker {
+	return &TrailingTracker{positions: make(map[int64]*TrackedPosition)}
+}
+
+// Track begins watching a position.
+func (t *TrailingTracker) Track(p *TrackedPosition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.positions[p.ID] = p
+}
+
+// Untrack stops watching a position (e.g. once it's closed).
+func (t *TrailingTracker) Untrack(id int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.positions, id)
+}
+
+// List returns every currently-tracked position.
+func (t *TrailingTracker) List() []*TrackedPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]*TrackedPosition, 0, len(t.positions))
+	for _, p := range t.positions {
+		out = append(out, p)
+	}
+	return out
+}
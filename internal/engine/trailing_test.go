@@ -0,0 +1,74 @@
+package engine
+
+import "testing"
+
+func TestTrackedPosition_PromotesTierOnActivation(t *testing.T) {
+	p := NewTrackedPosition(1, 34, "Tritanium", 100, 1000, nil)
+
+	if sellNow := p.OnPriceUpdate(100.3); sellNow {
+		t.Fatal("should not sell before any tier activates")
+	}
+	if p.ActiveTier != -1 {
+		t.Fatalf("ActiveTier = %d, want -1 before activation", p.ActiveTier)
+	}
+
+	// Crosses the 0.5% activation ratio (100.5).
+	p.OnPriceUpdate(100.6)
+	if p.ActiveTier != 0 {
+		t.Fatalf("ActiveTier = %d, want 0 after crossing first tier", p.ActiveTier)
+	}
+
+	// Crosses the 1% activation ratio (101), should promote to tier 1.
+	p.OnPriceUpdate(101.2)
+	if p.ActiveTier != 1 {
+		t.Fatalf("ActiveTier = %d, want 1 after crossing second tier", p.ActiveTier)
+	}
+}
+
+func TestTrackedPosition_CallbackFiresSellNow(t *testing.T) {
+	p := NewTrackedPosition(1, 34, "Tritanium", 100, 1000, []TrailingTier{
+		{ActivationRatio: 0.01, CallbackRate: 0.005},
+	})
+
+	p.OnPriceUpdate(101.5) // arms the tier, high-water mark = 101.5
+	if p.ActiveTier != 0 {
+		t.Fatalf("ActiveTier = %d, want 0", p.ActiveTier)
+	}
+
+	// Retrace by ~0.3%, below the 0.5% callback — should not fire yet.
+	if sellNow := p.OnPriceUpdate(101.2); sellNow {
+		t.Fatal("should not fire before callback threshold is reached")
+	}
+
+	// Retrace by >0.5% from the high-water mark — should fire.
+	if sellNow := p.OnPriceUpdate(100.9); !sellNow {
+		t.Fatal("expected sell-now signal once retracement exceeds callback rate")
+	}
+}
+
+func TestTrackedPosition_HighWaterMarkRisesWithPrice(t *testing.T) {
+	p := NewTrackedPosition(1, 34, "Tritanium", 100, 1000, nil)
+	p.OnPriceUpdate(105)
+	p.OnPriceUpdate(103) // dip shouldn't lower the high-water mark
+	if p.HighWaterMark != 105 {
+		t.Fatalf("HighWaterMark = %v, want 105", p.HighWaterMark)
+	}
+}
+
+func TestTrailingTracker_TrackUntrackList(t *testing.T) {
+	tr := NewTrailingTracker()
+	p1 := NewTrackedPosition(1, 34, "Tritanium", 5, 100, nil)
+	p2 := NewTrackedPosition(2, 35, "Pyerite", 10, 50, nil)
+
+	tr.Track(p1)
+	tr.Track(p2)
+	if len(tr.List()) != 2 {
+		t.Fatalf("List() len = %d, want 2", len(tr.List()))
+	}
+
+	tr.Untrack(1)
+	list := tr.List()
+	if len(list) != 1 || list[0].ID != 2 {
+		t.Fatalf("List() after Untrack = %+v, want only position 2", list)
+	}
+}
@@ -22,8 +22,20 @@ type MarketStats struct {
 	DailyVolume int64   // average daily volume over last 7 days
 	Velocity    float64 // daily_volume / total_listed_quantity
 	PriceTrend  float64 // % change over last 7 days (Theil-Sen slope)
+
+	ATR        float64 // Wilder-smoothed Average True Range over the WindowATR window (0 if insufficient history)
+	ATRPercent float64 // ATR normalized by mid-price, as a percent (0 if mid-price is unusable)
+
+	PivotHigh     float64 // most recent confirmed pivot high price (0 if none found)
+	PivotHighDate string  // date of PivotHigh, "" if none found
+	PivotLow      float64 // most recent confirmed pivot low price (0 if none found)
+	PivotLowDate  string  // date of PivotLow, "" if none found
 }
 
+// DefaultATRWindow is how many days of true-range history ComputeMarketStats
+// Wilder-smooths into ATR when the caller passes windowATR <= 0.
+const DefaultATRWindow = 14
+
 // HistoryCache is a persistent cache for market history data.
 type HistoryCache interface {
 	GetHistory(regionID int32, typeID int32) ([]HistoryEntry, bool)
@@ -43,7 +55,11 @@ func (c *Client) FetchMarketHistory(regionID, typeID int32) ([]HistoryEntry, err
 }
 
 // ComputeMarketStats computes trading statistics from history entries.
-func ComputeMarketStats(entries []HistoryEntry, totalListed int32) MarketStats {
+// windowATR controls how many days of true-range history are Wilder-smoothed
+// into ATR; values <= 0 fall back to DefaultATRWindow. pivotLength controls
+// how many confirming bars DetectPivots requires on each side; values <= 0
+// fall back to DefaultPivotLength.
+func ComputeMarketStats(entries []HistoryEntry, totalListed int32, windowATR int, pivotLength int) MarketStats {
 	if len(entries) == 0 {
 		return MarketStats{}
 	}
@@ -126,11 +142,89 @@ func ComputeMarketStats(entries []HistoryEntry, totalListed int32) MarketStats {
 		}
 	}
 
+	atr := wilderATR(sorted, windowATR)
+	atrPercent := 0.0
+	if midPrice := meanPrice(prices); midPrice > 0 {
+		atrPercent = atr / midPrice * 100
+	}
+
+	pivotHigh, pivotLow := DetectPivots(sorted, pivotLength)
+
 	return MarketStats{
-		DailyVolume: dailyVol,
-		Velocity:    velocity,
-		PriceTrend:  trend,
+		DailyVolume:   dailyVol,
+		Velocity:      velocity,
+		PriceTrend:    trend,
+		ATR:           atr,
+		ATRPercent:    atrPercent,
+		PivotHigh:     pivotHigh.Price,
+		PivotHighDate: pivotHigh.Date,
+		PivotLow:      pivotLow.Price,
+		PivotLowDate:  pivotLow.Date,
+	}
+}
+
+// meanPrice averages a slice of prices, returning 0 for an empty slice.
+func meanPrice(prices []float64) float64 {
+	if len(prices) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	return sum / float64(len(prices))
+}
+
+// trueRange is max(high-low, |high-prevClose|, |low-prevClose|).
+func trueRange(high, low, prevClose float64) float64 {
+	tr := high - low
+	if hc := math.Abs(high - prevClose); hc > tr {
+		tr = hc
+	}
+	if lc := math.Abs(low - prevClose); lc > tr {
+		tr = lc
+	}
+	return tr
+}
+
+// wilderATR computes Wilder-smoothed Average True Range over the trailing
+// window days of sorted (ascending-date) history, using each day's
+// Highest/Lowest and the prior day's Average price as a close proxy (ESI
+// history has no separate close field). The first ATR value seeds as the
+// simple mean of the first window true ranges; each subsequent day then
+// smooths via ATR = (prevATR*(window-1) + TR) / window. Degrades to a
+// simple mean of whatever true ranges exist when history is shorter than
+// window.
+func wilderATR(sorted []HistoryEntry, window int) float64 {
+	if len(sorted) < 2 {
+		return 0
+	}
+	if window <= 0 {
+		window = DefaultATRWindow
+	}
+
+	trueRanges := make([]float64, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		trueRanges = append(trueRanges, trueRange(sorted[i].Highest, sorted[i].Lowest, sorted[i-1].Average))
+	}
+	if len(trueRanges) == 0 {
+		return 0
+	}
+
+	seedLen := window
+	if seedLen > len(trueRanges) {
+		seedLen = len(trueRanges)
+	}
+	var sum float64
+	for _, tr := range trueRanges[:seedLen] {
+		sum += tr
+	}
+	atr := sum / float64(seedLen)
+
+	for _, tr := range trueRanges[seedLen:] {
+		atr = (atr*float64(window-1) + tr) / float64(window)
 	}
+	return atr
 }
 
 // medianSorted returns the median of a pre-sorted slice.
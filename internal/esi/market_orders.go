@@ -0,0 +1,101 @@
+package esi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultRegionBidCacheTTL is how long RegionBidOracle trusts a region's
+// cached order book before refetching, matching the staleness tolerance
+// corp.TTLPriceCache uses for its own provider cache.
+const DefaultRegionBidCacheTTL = 5 * time.Minute
+
+// RegionOrder is one active market order from /markets/{region_id}/orders/.
+type RegionOrder struct {
+	OrderID    int64   `json:"order_id"`
+	TypeID     int32   `json:"type_id"`
+	IsBuyOrder bool    `json:"is_buy_order"`
+	Price      float64 `json:"price"`
+}
+
+// FetchRegionBuyOrders fetches all active buy orders for a region from ESI.
+// Filtering server-side to order_type=buy keeps the response small since a
+// region's full order book can run into the tens of thousands of rows.
+func (c *Client) FetchRegionBuyOrders(regionID int64) ([]RegionOrder, error) {
+	url := fmt.Sprintf("%s/markets/%d/orders/?datasource=tranquility&order_type=buy", baseURL, regionID)
+
+	var orders []RegionOrder
+	if err := c.GetJSON(url, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// regionBidEntry is one region's cached order book snapshot.
+type regionBidEntry struct {
+	bids      map[int32]float64 // typeID -> best bid
+	expiresAt time.Time
+}
+
+// RegionBidOracle is a PriceOracle (see engine.PriceOracle) backed by live
+// ESI regional order books. It fetches and caches each region's best bid
+// per type for up to ttl, so a whole portfolio snapshot costs at most one
+// ESI call per distinct region rather than one per open position, while
+// still refreshing periodically instead of pinning the order book for the
+// life of the process.
+type RegionBidOracle struct {
+	client *Client
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	regionBids map[int64]regionBidEntry
+}
+
+// NewRegionBidOracle builds a RegionBidOracle backed by client, caching each
+// region's order book for ttl. A non-positive ttl defaults to
+// DefaultRegionBidCacheTTL.
+func NewRegionBidOracle(client *Client, ttl time.Duration) *RegionBidOracle {
+	if ttl <= 0 {
+		ttl = DefaultRegionBidCacheTTL
+	}
+	return &RegionBidOracle{
+		client:     client,
+		ttl:        ttl,
+		regionBids: make(map[int64]regionBidEntry),
+	}
+}
+
+// Bid returns the best (highest) live buy-order price for typeID in
+// regionID. ok is false when the region's order book couldn't be fetched
+// or carries no buy orders for typeID; callers should degrade to zero
+// unrealized P&L rather than treating it as an error.
+func (o *RegionBidOracle) Bid(typeID int32, regionID int64) (float64, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.regionBids[regionID]
+	if !ok || time.Now().UTC().After(entry.expiresAt) {
+		entry = regionBidEntry{bids: o.loadRegionBids(regionID), expiresAt: time.Now().UTC().Add(o.ttl)}
+		o.regionBids[regionID] = entry
+	}
+	price, ok := entry.bids[typeID]
+	return price, ok
+}
+
+func (o *RegionBidOracle) loadRegionBids(regionID int64) map[int32]float64 {
+	best := make(map[int32]float64)
+	orders, err := o.client.FetchRegionBuyOrders(regionID)
+	if err != nil {
+		return best
+	}
+	for _, ord := range orders {
+		if !ord.IsBuyOrder {
+			continue
+		}
+		if ord.Price > best[ord.TypeID] {
+			best[ord.TypeID] = ord.Price
+		}
+	}
+	return best
+}
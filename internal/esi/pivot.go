@@ -0,0 +1,75 @@
+package esi
+
+import "sort"
+
+// DefaultPivotLength is how many bars on each side of a candidate bar must
+// confirm it as a pivot high/low, when the caller doesn't override it.
+const DefaultPivotLength = 5
+
+// PivotPoint is a confirmed pivot high or low found in market history.
+type PivotPoint struct {
+	Price float64
+	Date  string
+}
+
+// DetectPivots scans entries for the most recent confirmed pivot high and
+// pivot low. A bar's Highest is a pivot high when it is strictly greater
+// than the Highest of the pivotLength bars before and after it; a pivot low
+// is analogous using Lowest. Entries need not be pre-sorted; DetectPivots
+// sorts a copy by date. Either return value is the zero PivotPoint if no
+// confirmed pivot of that kind exists (e.g. insufficient history).
+func DetectPivots(entries []HistoryEntry, pivotLength int) (high, low PivotPoint) {
+	if pivotLength <= 0 {
+		pivotLength = DefaultPivotLength
+	}
+	if len(entries) < 2*pivotLength+1 {
+		return PivotPoint{}, PivotPoint{}
+	}
+
+	sorted := make([]HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	// Scan from most recent back to oldest so the first match on each side
+	// is the most recent confirmed pivot; a pivot needs pivotLength
+	// confirming bars on both sides, so the newest possible pivot sits
+	// pivotLength bars before the end.
+	for i := len(sorted) - 1 - pivotLength; i >= pivotLength; i-- {
+		if high.Price == 0 && isPivotHigh(sorted, i, pivotLength) {
+			high = PivotPoint{Price: sorted[i].Highest, Date: sorted[i].Date}
+		}
+		if low.Price == 0 && isPivotLow(sorted, i, pivotLength) {
+			low = PivotPoint{Price: sorted[i].Lowest, Date: sorted[i].Date}
+		}
+		if high.Price != 0 && low.Price != 0 {
+			break
+		}
+	}
+	return high, low
+}
+
+func isPivotHigh(entries []HistoryEntry, i, k int) bool {
+	high := entries[i].Highest
+	for j := i - k; j <= i+k; j++ {
+		if j == i {
+			continue
+		}
+		if entries[j].Highest >= high {
+			return false
+		}
+	}
+	return true
+}
+
+func isPivotLow(entries []HistoryEntry, i, k int) bool {
+	low := entries[i].Lowest
+	for j := i - k; j <= i+k; j++ {
+		if j == i {
+			continue
+		}
+		if entries[j].Lowest <= low {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package esi
+
+import "testing"
+
+func flatEntries(n int, price float64) []HistoryEntry {
+	entries := make([]HistoryEntry, n)
+	for i := range entries {
+		entries[i] = HistoryEntry{
+			Date:    dateForIndex(i),
+			Highest: price,
+			Lowest:  price,
+		}
+	}
+	return entries
+}
+
+// dateForIndex renders a strictly increasing YYYY-MM-DD string so sort
+// order matches index order without pulling in time.Time arithmetic.
+func dateForIndex(i int) string {
+	day := 1 + i%28
+	month := 1 + i/28
+	return fmtDate(month, day)
+}
+
+func fmtDate(month, day int) string {
+	const digits = "0123456789"
+	b := []byte("2026-00-00")
+	b[5] = digits[month/10]
+	b[6] = digits[month%10]
+	b[8] = digits[day/10]
+	b[9] = digits[day%10]
+	return string(b)
+}
+
+func TestDetectPivots_InsufficientHistory(t *testing.T) {
+	entries := flatEntries(2*DefaultPivotLength, 100)
+	high, low := DetectPivots(entries, DefaultPivotLength)
+	if high.Price != 0 || low.Price != 0 {
+		t.Fatalf("DetectPivots with insufficient history = (%+v, %+v), want both zero", high, low)
+	}
+}
+
+func TestDetectPivots_FindsMostRecentConfirmedHighAndLow(t *testing.T) {
+	const k = 2
+	entries := flatEntries(2*k+3, 100)
+	// Plant a confirmed pivot high at index 2 (k bars of 100 on each side)
+	// and a confirmed pivot low at index 3.
+	entries[2].Highest = 150
+	entries[3].Lowest = 50
+
+	high, low := DetectPivots(entries, k)
+	if high.Price != 150 || high.Date != entries[2].Date {
+		t.Errorf("high = %+v, want price 150 at %s", high, entries[2].Date)
+	}
+	if low.Price != 50 || low.Date != entries[3].Date {
+		t.Errorf("low = %+v, want price 50 at %s", low, entries[3].Date)
+	}
+}
+
+func TestDetectPivots_UnconfirmedSpikeIsIgnored(t *testing.T) {
+	const k = 2
+	entries := flatEntries(2*k+3, 100)
+	// A spike on the last bar can never be confirmed: there aren't k bars
+	// after it.
+	entries[len(entries)-1].Highest = 500
+
+	high, _ := DetectPivots(entries, k)
+	if high.Price != 0 {
+		t.Errorf("high = %+v, want zero value (spike has no confirming bars after it)", high)
+	}
+}
+
+func TestDetectPivots_DefaultsLengthWhenNonPositive(t *testing.T) {
+	entries := flatEntries(2*DefaultPivotLength+3, 100)
+	entries[DefaultPivotLength+1].Highest = 200
+
+	high, _ := DetectPivots(entries, 0)
+	if high.Price != 200 {
+		t.Errorf("DetectPivots(entries, 0) high = %+v, want price 200 (should default pivotLength)", high)
+	}
+}
@@ -7,30 +7,21 @@ func (u *Universe) SystemsWithinRadius(origin int32, maxJumps int) map[int32]int
 }
 
 // SystemsWithinRadiusMinSecurity returns systems reachable within maxJumps where
-// every system on the path has security >= minSecurity. Use minSecurity <= 0 for no filter.
+// every system on the path has security >= minSecurity. Thin wrapper over
+// SystemsWithinCost with unit edge weight. Use minSecurity <= 0 for no filter.
 func (u *Universe) SystemsWithinRadiusMinSecurity(origin int32, maxJumps int, minSecurity float64) map[int32]int {
-	result := make(map[int32]int)
-	result[origin] = 0
-
-	queue := []int32{origin}
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		dist := result[current]
-		if dist >= maxJumps {
-			continue
-		}
-		for _, neighbor := range u.Adj[current] {
-			if minSecurity > 0 {
-				if sec, ok := u.SystemSecurity[neighbor]; !ok || sec < minSecurity {
-					continue
-				}
-			}
-			if _, visited := result[neighbor]; !visited {
-				result[neighbor] = dist + 1
-				queue = append(queue, neighbor)
+	weighted := u.SystemsWithinCost(origin, float64(maxJumps), func(from, to int32) float64 {
+		if minSecurity > 0 {
+			if sec, ok := u.SystemSecurity[to]; !ok || sec < minSecurity {
+				return -1
 			}
 		}
+		return 1
+	})
+
+	result := make(map[int32]int, len(weighted))
+	for sysID, cost := range weighted {
+		result[sysID] = int(cost)
 	}
 	return result
 }
@@ -43,7 +34,8 @@ func (u *Universe) ShortestPath(origin, dest int32) int {
 }
 
 // ShortestPathMinSecurity returns the shortest jump count using only systems with
-// security >= minSecurity. Uses BFS (all edges are unit weight).
+// security >= minSecurity. Thin wrapper over ShortestPathWeighted with unit
+// edge weight, since the unit-weight case is exactly what BFS already solved.
 // Use minSecurity <= 0 for no filter. Returns -1 if no path exists.
 func (u *Universe) ShortestPathMinSecurity(origin, dest int32, minSecurity float64) int {
 	if origin == dest {
@@ -58,32 +50,18 @@ func (u *Universe) ShortestPathMinSecurity(origin, dest int32, minSecurity float
 		}
 	}
 
-	dist := make(map[int32]int)
-	dist[origin] = 0
-
-	queue := []int32{origin}
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		currentDist := dist[current]
-
-		for _, neighbor := range u.Adj[current] {
-			if minSecurity > 0 {
-				if sec, ok := u.SystemSecurity[neighbor]; !ok || sec < minSecurity {
-					continue
-				}
-			}
-			if _, visited := dist[neighbor]; !visited {
-				nd := currentDist + 1
-				if neighbor == dest {
-					return nd
-				}
-				dist[neighbor] = nd
-				queue = append(queue, neighbor)
+	_, cost := u.ShortestPathWeighted(origin, dest, func(from, to int32) float64 {
+		if minSecurity > 0 {
+			if sec, ok := u.SystemSecurity[to]; !ok || sec < minSecurity {
+				return -1
 			}
 		}
+		return 1
+	})
+	if cost < 0 {
+		return -1
 	}
-	return -1
+	return int(cost)
 }
 
 // RegionsInSet returns the unique region IDs for a set of systems.
@@ -0,0 +1,148 @@
+package graph
+
+import "container/heap"
+
+// pqItem is one entry in the Dijkstra priority queue.
+type pqItem struct {
+	system int32
+	cost   float64
+	index  int
+}
+
+// priorityQueue is a min-heap of pqItem ordered by cost.
+type priorityQueue []*pqItem
+
+func (pq priorityQueue) Len() int           { return len(pq) }
+func (pq priorityQueue) Less(i, j int) bool { return pq[i].cost < pq[j].cost }
+func (pq priorityQueue) Swap(i, j int)      { pq[i], pq[j] = pq[j], pq[i]; pq[i].index = i; pq[j].index = j }
+func (pq *priorityQueue) Push(x interface{}) {
+	item := x.(*pqItem)
+	item.index = len(*pq)
+	*pq = append(*pq, item)
+}
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*pq = old[:n-1]
+	return item
+}
+
+// ShortestPathWeighted runs Dijkstra's algorithm from origin to dest using
+// the caller-supplied edge weight function, which lets callers penalize
+// low-sec/null-sec hops or add per-gate ISK cost instead of treating every
+// jump as unit cost. Returns the path (inclusive of origin and dest) and its
+// total cost, or a nil path and cost -1 if no path exists.
+func (u *Universe) ShortestPathWeighted(origin, dest int32, weight func(from, to int32) float64) ([]int32, float64) {
+	if origin == dest {
+		return []int32{origin}, 0
+	}
+
+	dist := map[int32]float64{origin: 0}
+	prev := map[int32]int32{}
+
+	pq := &priorityQueue{{system: origin, cost: 0}}
+	heap.Init(pq)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(*pqItem)
+		current := item.system
+		if item.cost > dist[current] {
+			continue // stale entry
+		}
+		if current == dest {
+			break
+		}
+
+		for _, neighbor := range u.Adj[current] {
+			w := weight(current, neighbor)
+			if w < 0 {
+				continue
+			}
+			nd := dist[current] + w
+			if d, visited := dist[neighbor]; !visited || nd < d {
+				dist[neighbor] = nd
+				prev[neighbor] = current
+				heap.Push(pq, &pqItem{system: neighbor, cost: nd})
+			}
+		}
+	}
+
+	finalCost, ok := dist[dest]
+	if !ok {
+		return nil, -1
+	}
+	return reconstructPath(prev, origin, dest), finalCost
+}
+
+// reconstructPath walks the predecessor map from dest back to origin.
+func reconstructPath(prev map[int32]int32, origin, dest int32) []int32 {
+	path := []int32{dest}
+	current := dest
+	for current != origin {
+		p, ok := prev[current]
+		if !ok {
+			return nil
+		}
+		path = append(path, p)
+		current = p
+	}
+	// Reverse into origin->dest order.
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// SystemsWithinCost is the weighted analogue of SystemsWithinRadius: it
+// returns every system reachable from origin whose cumulative edge cost
+// (per the weight function) is <= maxCost, mapped to that cost.
+func (u *Universe) SystemsWithinCost(origin int32, maxCost float64, weight func(from, to int32) float64) map[int32]float64 {
+	result := map[int32]float64{origin: 0}
+
+	pq := &priorityQueue{{system: origin, cost: 0}}
+	heap.Init(pq)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(*pqItem)
+		current := item.system
+		if item.cost > result[current] {
+			continue
+		}
+
+		for _, neighbor := range u.Adj[current] {
+			w := weight(current, neighbor)
+			if w < 0 {
+				continue
+			}
+			nd := result[current] + w
+			if nd > maxCost {
+				continue
+			}
+			if d, visited := result[neighbor]; !visited || nd < d {
+				result[neighbor] = nd
+				heap.Push(pq, &pqItem{system: neighbor, cost: nd})
+			}
+		}
+	}
+
+	return result
+}
+
+// SecurityWeight is a ready-made weight function penalizing low-sec and
+// null-sec hops: 1.0 for hi-sec, 5.0 for low-sec, 20.0 for null-sec/unknown.
+func (u *Universe) SecurityWeight(from, to int32) float64 {
+	sec, ok := u.SystemSecurity[to]
+	switch {
+	case !ok:
+		return 20.0
+	case sec >= 0.45:
+		return 1.0
+	case sec > 0:
+		return 5.0
+	default:
+		return 20.0
+	}
+}
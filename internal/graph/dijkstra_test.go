@@ -0,0 +1,125 @@
+package graph
+
+import "testing"
+
+// linearUniverse builds a -- b -- c -- d (undirected, unit-weight edges),
+// plus a disconnected system e with no edges at all.
+func linearUniverse() *Universe {
+	return &Universe{
+		Adj: map[int32][]int32{
+			1: {2},
+			2: {1, 3},
+			3: {2, 4},
+			4: {3},
+			5: {},
+		},
+	}
+}
+
+func TestShortestPathWeighted_OriginEqualsDest(t *testing.T) {
+	u := linearUniverse()
+	path, cost := u.ShortestPathWeighted(1, 1, func(int32, int32) float64 { return 1 })
+	if cost != 0 || len(path) != 1 || path[0] != 1 {
+		t.Fatalf("path, cost = %v, %v; want [1], 0", path, cost)
+	}
+}
+
+func TestShortestPathWeighted_FindsShortestRoute(t *testing.T) {
+	u := linearUniverse()
+	path, cost := u.ShortestPathWeighted(1, 4, func(int32, int32) float64 { return 1 })
+	want := []int32{1, 2, 3, 4}
+	if cost != 3 {
+		t.Errorf("cost = %v, want 3", cost)
+	}
+	if len(path) != len(want) {
+		t.Fatalf("path = %v, want %v", path, want)
+	}
+	for i, s := range want {
+		if path[i] != s {
+			t.Errorf("path[%d] = %v, want %v", i, path[i], s)
+		}
+	}
+}
+
+func TestShortestPathWeighted_NoPathReturnsNilAndNegativeCost(t *testing.T) {
+	u := linearUniverse()
+	path, cost := u.ShortestPathWeighted(1, 5, func(int32, int32) float64 { return 1 })
+	if path != nil || cost != -1 {
+		t.Errorf("path, cost = %v, %v; want nil, -1 (5 is disconnected)", path, cost)
+	}
+}
+
+func TestShortestPathWeighted_NegativeWeightEdgeIsTreatedAsImpassable(t *testing.T) {
+	u := linearUniverse()
+	// Block the direct 2->3 hop; the only route becomes impassable since
+	// there's no detour in this topology.
+	path, cost := u.ShortestPathWeighted(2, 3, func(from, to int32) float64 {
+		if from == 2 && to == 3 {
+			return -1
+		}
+		return 1
+	})
+	if path != nil || cost != -1 {
+		t.Errorf("path, cost = %v, %v; want nil, -1 (only edge is blocked)", path, cost)
+	}
+}
+
+func TestShortestPathWeighted_PrefersLowerWeightDetour(t *testing.T) {
+	// A diamond: 1->2->4 costs 2+2=4 direct, 1->3->4 costs 1+1=2.
+	u := &Universe{
+		Adj: map[int32][]int32{
+			1: {2, 3},
+			2: {4},
+			3: {4},
+			4: {},
+		},
+	}
+	weight := func(from, to int32) float64 {
+		if (from == 1 && to == 3) || (from == 3 && to == 4) {
+			return 1
+		}
+		return 2
+	}
+	path, cost := u.ShortestPathWeighted(1, 4, weight)
+	want := []int32{1, 3, 4}
+	if cost != 2 {
+		t.Errorf("cost = %v, want 2 (the cheaper detour through 3)", cost)
+	}
+	if len(path) != len(want) || path[1] != want[1] {
+		t.Errorf("path = %v, want %v", path, want)
+	}
+}
+
+func TestSystemsWithinCost_RespectsMaxCost(t *testing.T) {
+	u := linearUniverse()
+	got := u.SystemsWithinCost(1, 2, func(int32, int32) float64 { return 1 })
+	want := map[int32]float64{1: 0, 2: 1, 3: 2}
+	if len(got) != len(want) {
+		t.Fatalf("got = %v, want %v", got, want)
+	}
+	for sys, cost := range want {
+		if got[sys] != cost {
+			t.Errorf("got[%d] = %v, want %v", sys, got[sys], cost)
+		}
+	}
+}
+
+func TestSecurityWeight_BucketsBySecurityStatus(t *testing.T) {
+	u := &Universe{SystemSecurity: map[int32]float64{
+		1: 0.9,  // hi-sec
+		2: 0.3,  // low-sec
+		3: -0.1, // null-sec
+	}}
+	if w := u.SecurityWeight(0, 1); w != 1.0 {
+		t.Errorf("hi-sec weight = %v, want 1.0", w)
+	}
+	if w := u.SecurityWeight(0, 2); w != 5.0 {
+		t.Errorf("low-sec weight = %v, want 5.0", w)
+	}
+	if w := u.SecurityWeight(0, 3); w != 20.0 {
+		t.Errorf("null-sec weight = %v, want 20.0", w)
+	}
+	if w := u.SecurityWeight(0, 999); w != 20.0 {
+		t.Errorf("unknown-system weight = %v, want 20.0", w)
+	}
+}
@@ -0,0 +1,143 @@
+package graph
+
+import "sort"
+
+// yenCandidate is a candidate path awaiting selection in Yen's algorithm.
+type yenCandidate struct {
+	path []int32
+	cost float64
+}
+
+// KShortestPaths returns up to k distinct loopless paths from origin to dest,
+// sorted by ascending total cost, using Yen's algorithm on top of the
+// security-weighted Dijkstra (SecurityWeight). Returns fewer than k paths if
+// fewer exist.
+func (u *Universe) KShortestPaths(origin, dest int32, k int, minSecurity float64) [][]int32 {
+	if k <= 0 || origin == dest {
+		return nil
+	}
+
+	weight := func(from, to int32) float64 {
+		if minSecurity > 0 {
+			if sec, ok := u.SystemSecurity[to]; !ok || sec < minSecurity {
+				return -1
+			}
+		}
+		return u.SecurityWeight(from, to)
+	}
+
+	firstPath, firstCost := u.ShortestPathWeighted(origin, dest, weight)
+	if firstPath == nil {
+		return nil
+	}
+
+	paths := [][]int32{firstPath}
+	costs := []float64{firstCost}
+	var candidates []yenCandidate
+
+	for i := 1; i < k; i++ {
+		prevPath := paths[i-1]
+
+		for spurIdx := 0; spurIdx < len(prevPath)-1; spurIdx++ {
+			spurNode := prevPath[spurIdx]
+			rootPath := append([]int32(nil), prevPath[:spurIdx+1]...)
+
+			removedEdges := map[[2]int32]bool{}
+			for _, p := range paths {
+				if len(p) > spurIdx && pathsSharePrefix(p, rootPath) {
+					removedEdges[[2]int32{p[spurIdx], p[spurIdx+1]}] = true
+				}
+			}
+			removedNodes := map[int32]bool{}
+			for _, n := range rootPath[:len(rootPath)-1] {
+				removedNodes[n] = true
+			}
+
+			spurWeight := func(from, to int32) float64 {
+				if removedNodes[to] {
+					return -1
+				}
+				if removedEdges[[2]int32{from, to}] {
+					return -1
+				}
+				return weight(from, to)
+			}
+
+			spurPath, spurCost := u.ShortestPathWeighted(spurNode, dest, spurWeight)
+			if spurPath == nil {
+				continue
+			}
+
+			rootCost := pathCost(rootPath, weight)
+			totalPath := append(append([]int32(nil), rootPath[:len(rootPath)-1]...), spurPath...)
+			totalCost := rootCost + spurCost
+
+			if !containsPath(paths, totalPath) && !containsCandidate(candidates, totalPath) {
+				candidates = append(candidates, yenCandidate{path: totalPath, cost: totalCost})
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		sort.Slice(candidates, func(a, b int) bool { return candidates[a].cost < candidates[b].cost })
+		best := candidates[0]
+		candidates = candidates[1:]
+
+		paths = append(paths, best.path)
+		costs = append(costs, best.cost)
+	}
+
+	return paths
+}
+
+func pathsSharePrefix(p, prefix []int32) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i, n := range prefix {
+		if p[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+func pathCost(path []int32, weight func(from, to int32) float64) float64 {
+	var cost float64
+	for i := 0; i+1 < len(path); i++ {
+		cost += weight(path[i], path[i+1])
+	}
+	return cost
+}
+
+func containsPath(paths [][]int32, candidate []int32) bool {
+	for _, p := range paths {
+		if equalPath(p, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCandidate(candidates []yenCandidate, candidate []int32) bool {
+	for _, c := range candidates {
+		if equalPath(c.path, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalPath(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
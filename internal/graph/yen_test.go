@@ -0,0 +1,100 @@
+package graph
+
+import "testing"
+
+// branchedUniverse is two parallel two-hop routes from 1 to 6 that merge at
+// 4: 1->2->4->6 through hi-sec system 2, and 1->3->4->6 through low-sec
+// system 3, so KShortestPaths' SecurityWeight-based cost ordering and
+// minSecurity filtering both have something to bite on.
+func branchedUniverse() *Universe {
+	return &Universe{
+		Adj: map[int32][]int32{
+			1: {2, 3},
+			2: {4},
+			3: {4},
+			4: {6},
+			6: {},
+		},
+		SystemSecurity: map[int32]float64{
+			2: 0.9, // hi-sec
+			3: 0.3, // low-sec
+			4: 0.9,
+			6: 0.9,
+		},
+	}
+}
+
+func wantPath(t *testing.T, got []int32, want []int32) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("path = %v, want %v", got, want)
+	}
+	for i, s := range want {
+		if got[i] != s {
+			t.Errorf("path[%d] = %v, want %v", i, got[i], s)
+		}
+	}
+}
+
+func TestKShortestPaths_OrdersByAscendingCost(t *testing.T) {
+	u := branchedUniverse()
+	paths := u.KShortestPaths(1, 6, 2, 0)
+	if len(paths) != 2 {
+		t.Fatalf("len(paths) = %d, want 2", len(paths))
+	}
+	wantPath(t, paths[0], []int32{1, 2, 4, 6}) // hi-sec route: cheaper
+	wantPath(t, paths[1], []int32{1, 3, 4, 6}) // low-sec route: pricier
+}
+
+func TestKShortestPaths_ReturnsFewerThanKWhenFewerExist(t *testing.T) {
+	u := branchedUniverse()
+	paths := u.KShortestPaths(1, 6, 5, 0)
+	if len(paths) != 2 {
+		t.Fatalf("len(paths) = %d, want 2 (only two loopless routes exist)", len(paths))
+	}
+}
+
+func TestKShortestPaths_MinSecurityFiltersOutLowSecRoute(t *testing.T) {
+	u := branchedUniverse()
+	paths := u.KShortestPaths(1, 6, 2, 0.45)
+	if len(paths) != 1 {
+		t.Fatalf("len(paths) = %d, want 1 (the low-sec route through 3 should be filtered out)", len(paths))
+	}
+	wantPath(t, paths[0], []int32{1, 2, 4, 6})
+}
+
+func TestKShortestPaths_ZeroKOrSameOriginDestReturnsNil(t *testing.T) {
+	u := branchedUniverse()
+	if got := u.KShortestPaths(1, 6, 0, 0); got != nil {
+		t.Errorf("KShortestPaths(k=0) = %v, want nil", got)
+	}
+	if got := u.KShortestPaths(1, 1, 3, 0); got != nil {
+		t.Errorf("KShortestPaths(origin==dest) = %v, want nil", got)
+	}
+}
+
+func TestKShortestPaths_NoPathReturnsNil(t *testing.T) {
+	u := &Universe{Adj: map[int32][]int32{1: {}, 2: {}}}
+	if got := u.KShortestPaths(1, 2, 3, 0); got != nil {
+		t.Errorf("KShortestPaths with no route = %v, want nil", got)
+	}
+}
+
+func TestPathCost_SumsEdgeWeights(t *testing.T) {
+	got := pathCost([]int32{1, 2, 3}, func(from, to int32) float64 { return float64(to - from) })
+	if got != 2 {
+		t.Errorf("pathCost = %v, want 2", got)
+	}
+}
+
+func TestEqualPath(t *testing.T) {
+	if !equalPath([]int32{1, 2, 3}, []int32{1, 2, 3}) {
+		t.Error("equalPath(identical) = false, want true")
+	}
+	if equalPath([]int32{1, 2, 3}, []int32{1, 2}) {
+		t.Error("equalPath(different length) = true, want false")
+	}
+	if equalPath([]int32{1, 2, 3}, []int32{1, 2, 4}) {
+		t.Error("equalPath(same length, differing element) = true, want false")
+	}
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"container/ring"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level is a log severity, ordered from most to least verbose.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Record is one structured log entry, matching the {ts, level, tag, msg,
+// fields...} shape operators can pipe into jq or ship to an aggregator.
+type Record struct {
+	Time   time.Time      `json:"ts"`
+	Level  string         `json:"level"`
+	Tag    string         `json:"tag"`
+	Msg    string         `json:"msg"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// Sink receives every Record that passes the Logger's level filter.
+type Sink interface {
+	Write(Record)
+}
+
+// prettySink renders records through the existing ANSI pretty-printer
+// functions, preserving the look of the original package-level API.
+type prettySink struct{}
+
+func (prettySink) Write(r Record) {
+	line := r.Msg
+	if len(r.Fields) > 0 {
+		line = fmt.Sprintf("%s %v", r.Msg, r.Fields)
+	}
+	switch r.Level {
+	case LevelDebug.String():
+		Info(r.Tag, line)
+	case LevelWarn.String():
+		Warn(r.Tag, line)
+	case LevelError.String():
+		Error(r.Tag, line)
+	default:
+		Info(r.Tag, line)
+	}
+}
+
+// JSONSink writes one JSON object per line to w.
+type JSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONSink wraps an io.Writer (stdout, a rotating file, a socket) as a
+// JSON-lines sink.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+func (s *JSONSink) Write(r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(s.w)
+	enc.Encode(r)
+}
+
+// RingSink keeps the last N records in memory for the UI to poll, e.g. a
+// live "recent log lines" panel without tailing a file.
+type RingSink struct {
+	mu  sync.Mutex
+	buf *ring.Ring
+}
+
+// NewRingSink creates a ring buffer sink holding up to capacity records.
+func NewRingSink(capacity int) *RingSink {
+	if capacity <= 0 {
+		capacity = 200
+	}
+	return &RingSink{buf: ring.New(capacity)}
+}
+
+func (s *RingSink) Write(r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Value = r
+	s.buf = s.buf.Next()
+}
+
+// Records returns the buffered records in chronological order (oldest first).
+func (s *RingSink) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Record
+	s.buf.Do(func(v any) {
+		if v == nil {
+			return
+		}
+		out = append(out, v.(Record))
+	})
+	return out
+}
+
+// Logger is a configurable logger with a minimum level and pluggable sinks.
+// The package-level Info/Success/Warn/Error/Loading functions remain the
+// default pretty-printer; Logger is for call sites that want levels,
+// structured fields, or a non-stdout destination.
+type Logger struct {
+	mu       sync.Mutex
+	minLevel Level
+	sinks    []Sink
+	fields   map[string]any
+}
+
+// Default is the process-wide logger used by the package-level helpers
+// below. It starts with the pretty-printer as its only sink, matching the
+// existing default behavior.
+var Default = New(LevelInfo, prettySink{})
+
+// New creates a Logger at the given minimum level writing to the given sinks.
+// If no sinks are given, it defaults to the pretty-printer.
+func New(minLevel Level, sinks ...Sink) *Logger {
+	if len(sinks) == 0 {
+		sinks = []Sink{prettySink{}}
+	}
+	return &Logger{minLevel: minLevel, sinks: sinks}
+}
+
+// AddSink attaches an additional sink (e.g. a JSONSink or RingSink) without
+// replacing the existing ones.
+func (l *Logger) AddSink(s Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sinks = append(l.sinks, s)
+}
+
+// SetLevel changes the minimum level records must meet to be emitted.
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.minLevel = level
+}
+
+// WithFields returns a derived Logger that attaches the given fields to
+// every record it emits, leaving the receiver untouched.
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{minLevel: l.minLevel, sinks: l.sinks, fields: merged}
+}
+
+func (l *Logger) log(level Level, tag, msg string) {
+	if level < l.minLevel {
+		return
+	}
+	r := Record{
+		Time:   time.Now().UTC(),
+		Level:  level.String(),
+		Tag:    tag,
+		Msg:    msg,
+		Fields: l.fields,
+	}
+	l.mu.Lock()
+	sinks := l.sinks
+	l.mu.Unlock()
+	for _, s := range sinks {
+		s.Write(r)
+	}
+}
+
+// Debug logs at debug level.
+func (l *Logger) Debug(tag, msg string) { l.log(LevelDebug, tag, msg) }
+
+// Infof logs at info level (named to avoid colliding with the package-level Info).
+func (l *Logger) Infof(tag, msg string) { l.log(LevelInfo, tag, msg) }
+
+// Warnf logs at warn level.
+func (l *Logger) Warnf(tag, msg string) { l.log(LevelWarn, tag, msg) }
+
+// Errorf logs at error level.
+func (l *Logger) Errorf(tag, msg string) { l.log(LevelError, tag, msg) }
+
+// Duration logs a consistently-formatted scan-timing record at debug level,
+// e.g. Duration("scan", "contracts", 1.2*time.Second).
+func (l *Logger) Duration(tag, label string, d time.Duration) {
+	l.WithFields(map[string]any{"duration_ms": d.Milliseconds()}).log(LevelDebug, tag, fmt.Sprintf("%s took %s", label, d.Round(time.Millisecond)))
+}
+
+// SetJSONOutput switches the default logger to emit JSON lines to w instead
+// of (or in addition to) the pretty printer.
+func SetJSONOutput(w io.Writer, alsoPretty bool) {
+	sinks := []Sink{NewJSONSink(w)}
+	if alsoPretty {
+		sinks = append(sinks, prettySink{})
+	}
+	Default.mu.Lock()
+	Default.sinks = sinks
+	Default.mu.Unlock()
+}
+
+// StdoutJSON is a convenience helper for SetJSONOutput(os.Stdout, false).
+func StdoutJSON() {
+	SetJSONOutput(os.Stdout, false)
+}
@@ -0,0 +1,36 @@
+// Package money provides fixed-point ISK arithmetic helpers built on
+// shopspring/decimal. ESI wallet/journal amounts already carry 2 decimal
+// places, and repeated float64 summation of those amounts drifts once
+// totals reach the tens of billions of ISK that a mature corporation
+// accumulates. Callers should parse/convert to Decimal once at the edge of
+// the data they're summing, do all arithmetic in Decimal, and only convert
+// back to float64 (via ToFloat) at the point where the result is assigned
+// into a struct field that serializes to JSON.
+package money
+
+import "github.com/shopspring/decimal"
+
+// FromFloat converts a float64 ISK amount, as decoded from ESI's JSON
+// responses, into a Decimal. This is the one place float64 imprecision can
+// reappear, since ESI itself serializes amounts as JSON numbers rather
+// than strings; every sum downstream of this call should stay in Decimal.
+func FromFloat(v float64) decimal.Decimal {
+	return decimal.NewFromFloat(v)
+}
+
+// ToFloat converts back to float64 for struct fields that haven't
+// migrated to Decimal.
+func ToFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
+
+// PercentOf returns (part/whole)*100 rounded to 1 decimal place, or zero
+// when whole is zero. Replaces the math.Round(pct*10)/10 float64 idiom
+// without its rounding drift.
+func PercentOf(part, whole decimal.Decimal) decimal.Decimal {
+	if whole.IsZero() {
+		return decimal.Zero
+	}
+	return part.Div(whole).Mul(decimal.NewFromInt(100)).Round(1)
+}
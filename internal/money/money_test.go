@@ -0,0 +1,39 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFromFloat_ToFloat_RoundTrips(t *testing.T) {
+	got := ToFloat(FromFloat(1234.56))
+	if got != 1234.56 {
+		t.Fatalf("round trip = %v, want 1234.56", got)
+	}
+}
+
+func TestPercentOf_ZeroWholeReturnsZero(t *testing.T) {
+	got := PercentOf(decimal.NewFromInt(10), decimal.Zero)
+	if !got.IsZero() {
+		t.Fatalf("PercentOf with zero whole = %v, want 0", got)
+	}
+}
+
+func TestPercentOf_RoundsToOneDecimalPlace(t *testing.T) {
+	got := PercentOf(decimal.NewFromFloat(1), decimal.NewFromFloat(3))
+	want := decimal.NewFromFloat(33.3)
+	if !got.Equal(want) {
+		t.Fatalf("PercentOf(1, 3) = %v, want %v", got, want)
+	}
+}
+
+func TestFromFloat_RepeatedAdditionDoesNotDrift(t *testing.T) {
+	sum := decimal.Zero
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(FromFloat(0.1))
+	}
+	if !sum.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("sum of 10x0.1 = %v, want exactly 1", sum)
+	}
+}